@@ -44,6 +44,8 @@ type Base struct {
 	enabled   bool
 	invalid   bool
 	errorText string
+
+	direction Direction
 }
 
 func NewBase(cfg *WidgetBaseConfig) Base {
@@ -211,6 +213,23 @@ func (c *Base) DrawInvalid(ctx *Context, dst *ebiten.Image, r image.Rectangle) {
 	drawErrorText(ctx, dst, err, c.errorText)
 }
 
+// SetDirection overrides the Context text direction for this widget.
+// Use DirectionInherit to follow the Context again.
+func (b *Base) SetDirection(d Direction) { b.direction = d }
+
+// Direction returns the direction set on this widget (may be DirectionInherit).
+func (b *Base) Direction() Direction { return b.direction }
+
+// IsRTL reports whether the widget lays out right-to-left, resolving
+// DirectionInherit against the Context.
+func (b *Base) IsRTL(ctx *Context) bool {
+	if b.direction != DirectionInherit {
+		return b.direction == DirectionRTL
+	}
+
+	return ctx.Direction() == DirectionRTL
+}
+
 func (c *Base) Theme() *Theme {
 	return c.theme
 }
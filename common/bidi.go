@@ -0,0 +1,301 @@
+package common
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/bidi"
+)
+
+// Bidirectional text is reordered per line for display following a reduced
+// form of the Unicode Bidirectional Algorithm (UAX #9): weak and neutral type
+// resolution, implicit levels and line reordering. Explicit embeddings and
+// isolates are treated as boundary neutrals.
+
+// BidiCluster is a grapheme cluster of a line laid out by BidiLayout, in
+// visual order.
+type BidiCluster struct {
+	Start, End   int // logical byte range
+	VStart, VEnd int // byte range inside the visual text
+	RTL          bool
+}
+
+// mirrored holds the glyph substitutions applied to characters at RTL levels.
+var mirrored = map[rune]rune{
+	'(': ')', ')': '(', '[': ']', ']': '[', '{': '}', '}': '{', '<': '>', '>': '<',
+	'«': '»', '»': '«', '‹': '›', '›': '‹',
+}
+
+// BidiLayout reorders a single line (without '\n') for display. It returns
+// the visual text and its clusters, from left to right.
+func BidiLayout(s string, rtl bool) (string, []BidiCluster) {
+	base := uint8(0)
+	if rtl {
+		base = 1
+	}
+
+	var levels []uint8
+	if rtl || NeedsBidi(s) {
+		levels = BidiLevels(s, base)
+	}
+
+	// Clusters take the level of their first rune.
+	var clusters []BidiCluster
+	var clusterLevels []uint8
+	runeIdx, pos, state := 0, 0, -1
+	rest := s
+	for len(rest) > 0 {
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+
+		lvl := base &^ 1
+		if levels != nil {
+			lvl = levels[runeIdx]
+		}
+
+		clusters = append(clusters, BidiCluster{Start: pos, End: pos + len(cluster), RTL: lvl%2 == 1})
+		clusterLevels = append(clusterLevels, lvl)
+
+		runeIdx += utf8.RuneCountInString(cluster)
+		pos += len(cluster)
+	}
+
+	reorderClusters(clusters, clusterLevels)
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := range clusters {
+		c := &clusters[i]
+		c.VStart = sb.Len()
+
+		cluster := s[c.Start:c.End]
+		if r, ok := mirrored[[]rune(cluster)[0]]; ok && c.RTL && utf8.RuneCountInString(cluster) == 1 {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteString(cluster)
+		}
+		c.VEnd = sb.Len()
+	}
+
+	return sb.String(), clusters
+}
+
+// VisualText reorders every line of s for display.
+func VisualText(s string, rtl bool) string {
+	if !rtl && !NeedsBidi(s) {
+		return s
+	}
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i], _ = BidiLayout(line, rtl)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// NeedsBidi reports whether s contains characters that can be RTL.
+func NeedsBidi(s string) bool {
+	for _, r := range s {
+		if r >= 0x0590 {
+			if p, _ := bidi.LookupRune(r); p.Class() == bidi.R || p.Class() == bidi.AL || p.Class() == bidi.AN {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// reorderClusters applies rule L2: from the highest level down to the lowest
+// odd level, reverse every sequence at that level or higher.
+func reorderClusters(cs []BidiCluster, levels []uint8) {
+	var hi, loOdd uint8 = 0, 255
+	for _, l := range levels {
+		hi = max(hi, l)
+		if l%2 == 1 {
+			loOdd = min(loOdd, l)
+		}
+	}
+
+	for lvl := hi; lvl >= loOdd && lvl > 0; lvl-- {
+		for i := 0; i < len(cs); {
+			if levels[i] < lvl {
+				i++
+				continue
+			}
+
+			j := i
+			for j < len(cs) && levels[j] >= lvl {
+				j++
+			}
+			for a, b := i, j-1; a < b; a, b = a+1, b-1 {
+				cs[a], cs[b] = cs[b], cs[a]
+				levels[a], levels[b] = levels[b], levels[a]
+			}
+			i = j
+		}
+	}
+}
+
+// BidiLevels resolves the embedding level of every rune of s.
+func BidiLevels(s string, base uint8) []uint8 {
+	types := make([]bidi.Class, 0, len(s))
+	for _, r := range s {
+		p, _ := bidi.LookupRune(r)
+		c := p.Class()
+		if c > bidi.AL {
+			c = bidi.BN
+		}
+		types = append(types, c)
+	}
+
+	sos := bidi.L
+	if base%2 == 1 {
+		sos = bidi.R
+	}
+
+	// W1: non-spacing marks take the type of the previous character.
+	prev := sos
+	for i, t := range types {
+		if t == bidi.NSM {
+			types[i] = prev
+		} else if t != bidi.BN {
+			prev = t
+		}
+	}
+
+	// W2, W3: European numbers after Arabic letters become Arabic numbers;
+	// Arabic letters become R.
+	last := sos
+	for i, t := range types {
+		switch t {
+		case bidi.L, bidi.R, bidi.AL:
+			last = t
+		case bidi.EN:
+			if last == bidi.AL {
+				types[i] = bidi.AN
+			}
+		}
+	}
+	for i, t := range types {
+		if t == bidi.AL {
+			types[i] = bidi.R
+		}
+	}
+
+	// W4: a single separator between two numbers of the same kind joins them.
+	for i := 1; i+1 < len(types); i++ {
+		a, b := types[i-1], types[i+1]
+		switch {
+		case types[i] == bidi.ES && a == bidi.EN && b == bidi.EN:
+			types[i] = bidi.EN
+		case types[i] == bidi.CS && a == b && (a == bidi.EN || a == bidi.AN):
+			types[i] = a
+		}
+	}
+
+	// W5: terminators adjacent to European numbers become numbers.
+	for i := 0; i < len(types); i++ {
+		if types[i] != bidi.ET {
+			continue
+		}
+		j := i
+		for j < len(types) && types[j] == bidi.ET {
+			j++
+		}
+		if (i > 0 && types[i-1] == bidi.EN) || (j < len(types) && types[j] == bidi.EN) {
+			for k := i; k < j; k++ {
+				types[k] = bidi.EN
+			}
+		}
+		i = j
+	}
+
+	// W6, W7: remaining separators are neutral; European numbers in an L
+	// context become L.
+	last = sos
+	for i, t := range types {
+		switch t {
+		case bidi.ES, bidi.ET, bidi.CS:
+			types[i] = bidi.ON
+		case bidi.L, bidi.R:
+			last = t
+		case bidi.EN:
+			if last == bidi.L {
+				types[i] = bidi.L
+			}
+		}
+	}
+
+	// N1, N2: neutrals take the direction of surrounding strong text when both
+	// sides agree, otherwise the embedding direction. Numbers count as R.
+	strong := func(t bidi.Class) (bidi.Class, bool) {
+		switch t {
+		case bidi.L:
+			return bidi.L, true
+		case bidi.R, bidi.EN, bidi.AN:
+			return bidi.R, true
+		}
+		return 0, false
+	}
+	for i := 0; i < len(types); i++ {
+		if _, ok := strong(types[i]); ok {
+			continue
+		}
+
+		j := i
+		for j < len(types) {
+			if _, ok := strong(types[j]); ok {
+				break
+			}
+			j++
+		}
+
+		before, after := sos, sos
+		if i > 0 {
+			before, _ = strong(types[i-1])
+		}
+		if j < len(types) {
+			after, _ = strong(types[j])
+		}
+
+		dir := sos
+		if before == after {
+			dir = before
+		}
+		for k := i; k < j; k++ {
+			types[k] = dir
+		}
+		i = j
+	}
+
+	// I1, I2: implicit levels.
+	levels := make([]uint8, len(types))
+	for i, t := range types {
+		lvl := base
+		if base%2 == 0 {
+			switch t {
+			case bidi.R:
+				lvl++
+			case bidi.AN, bidi.EN:
+				lvl += 2
+			}
+		} else if t == bidi.L || t == bidi.EN || t == bidi.AN {
+			lvl++
+		}
+		levels[i] = lvl
+	}
+
+	// L1: trailing whitespace goes back to the paragraph level.
+	i := len(s)
+	for k := len(levels) - 1; k >= 0; k-- {
+		r, sz := utf8.DecodeLastRuneInString(s[:i])
+		i -= sz
+		if p, _ := bidi.LookupRune(r); p.Class() != bidi.WS && p.Class() != bidi.S && p.Class() != bidi.BN {
+			break
+		}
+		levels[k] = base
+	}
+
+	return levels
+}
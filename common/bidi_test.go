@@ -0,0 +1,137 @@
+package common
+
+import (
+	"slices"
+	"testing"
+)
+
+// Hebrew and Arabic letters are strong RTL; the visual strings below are
+// written left to right as they appear on screen.
+
+func TestBidiLayout(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		rtl  bool
+		want string
+	}{
+		{"empty", "", false, ""},
+		{"ltr text", "abc", false, "abc"},
+		{"ltr text in rtl paragraph", "abc", true, "abc"},
+		{"rtl text", "אבג", false, "גבא"},
+		{"rtl run in ltr", "abc אבג def", false, "abc גבא def"},
+		{"ltr run in rtl", "אבג abc דה", true, "הד abc גבא"},
+		{"numbers after rtl in ltr", "אבג 123", false, "123 גבא"},
+		{"numbers inside rtl", "אב 123 גד", true, "דג 123 בא"},
+		{"arabic numbers", "اب 12 ج", true, "ج 12 با"},
+		{"number separators and terminators", "12.5% א", true, "א 12.5%"},
+		{"mirrored brackets", "(אב)", true, "(בא)"},
+		{"brackets around rtl in ltr", "abc (אב)", false, "abc (בא)"},
+		{"trailing space keeps paragraph level", "אב ", false, "בא "},
+		{"combining mark stays on its base", "e\u0301\u05d0", false, "e\u0301\u05d0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := BidiLayout(tt.s, tt.rtl); got != tt.want {
+				t.Errorf("BidiLayout(%q, %v) = %q, want %q", tt.s, tt.rtl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBidiLevels(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		base uint8
+		want []uint8
+	}{
+		{"ltr", "ab", 0, []uint8{0, 0}},
+		{"ltr in rtl paragraph", "ab", 1, []uint8{2, 2}},
+		{"neutral between rtl runs", "א ב", 0, []uint8{1, 1, 1}},
+		{"neutral between mixed runs", "a ב", 0, []uint8{0, 0, 1}},
+		{"european number after rtl", "א 1", 0, []uint8{1, 1, 2}},
+		{"european number in ltr", "a 1", 0, []uint8{0, 0, 0}},
+		{"arabic number after arabic letter", "ا1", 0, []uint8{1, 2}},
+		{"trailing whitespace", "א  ", 0, []uint8{1, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BidiLevels(tt.s, tt.base); !slices.Equal(got, tt.want) {
+				t.Errorf("BidiLevels(%q, %d) = %v, want %v", tt.s, tt.base, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBidiClusters(t *testing.T) {
+	// "ab אב": the Hebrew letters are shown reversed after the Latin ones.
+	s := "ab אב"
+	alef, bet := 3, 5
+	visual, cs := BidiLayout(s, false)
+
+	want := []BidiCluster{
+		{Start: 0, End: 1},
+		{Start: 1, End: 2},
+		{Start: 2, End: 3},
+		{Start: bet, End: bet + 2, RTL: true},
+		{Start: alef, End: alef + 2, RTL: true},
+	}
+	if len(cs) != len(want) {
+		t.Fatalf("got %d clusters, want %d", len(cs), len(want))
+	}
+
+	vpos := 0
+	for i, c := range cs {
+		if c.Start != want[i].Start || c.End != want[i].End || c.RTL != want[i].RTL {
+			t.Errorf("cluster %d = %+v, want %+v", i, c, want[i])
+		}
+		if c.VStart != vpos || visual[c.VStart:c.VEnd] != s[c.Start:c.End] {
+			t.Errorf("cluster %d visual range [%d, %d) doesn't follow the previous one", i, c.VStart, c.VEnd)
+		}
+		vpos = c.VEnd
+	}
+}
+
+func TestNeedsBidi(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{"", false},
+		{"plain ascii 123", false},
+		{"accents éàü and emoji 👍", false},
+		{"hebrew א", true},
+		{"arabic ب", true},
+		{"arabic-indic digits ١٢", true},
+	}
+
+	for _, tt := range tests {
+		if got := NeedsBidi(tt.s); got != tt.want {
+			t.Errorf("NeedsBidi(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestVisualText(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		rtl  bool
+		want string
+	}{
+		{"ltr untouched", "a (b)\nc", false, "a (b)\nc"},
+		{"lines reordered separately", "אב\nabc גד", false, "בא\nabc דג"},
+		{"rtl paragraph", "אב 1\nג", true, "1 בא\nג"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VisualText(tt.s, tt.rtl); got != tt.want {
+				t.Errorf("VisualText(%q, %v) = %q, want %q", tt.s, tt.rtl, got, tt.want)
+			}
+		})
+	}
+}
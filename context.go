@@ -2,6 +2,7 @@ package uikit
 
 import (
 	"image"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
@@ -15,9 +16,36 @@ type Context struct {
 	widgets []Widget
 	focus   int // -1 means none
 
+	// focus at the start of the current Update, before widgets moved it
+	frameFocus Widget
+
+	direction Direction
+
 	ptr         *PointerStatus
 	hasTouch    bool
 	prevTouches map[ebiten.TouchID]struct{}
+	capture     Widget
+
+	clock func() time.Time
+	now   time.Time
+
+	dialogs      []modal
+	restoreFocus Widget
+	screen       image.Rectangle
+
+	toasts      []*Toast
+	toastCorner ToastCorner
+	toastDrag   *Toast
+	toastPress  bool        // an action button press is held
+	toastPtr    image.Point // pointer position before the toast layer hides it
+	toastTick   time.Time
+}
+
+// modal is an open dialog or popup and the widget focused before it opened.
+type modal struct {
+	w         ModalWidget
+	prevFocus Widget
+	backdrop  bool
 }
 
 func NewContext(theme *Theme, root Layout, ime IMEBridge) *Context {
@@ -36,6 +64,44 @@ func (c *Context) Theme() *Theme {
 	return c.theme
 }
 
+// SetDirection sets the base text direction for all widgets that don't
+// override it with Base.SetDirection.
+func (c *Context) SetDirection(d Direction) {
+	c.direction = d
+}
+
+// Direction returns the base text direction (DirectionLTR by default).
+func (c *Context) Direction() Direction {
+	if c.direction == DirectionInherit {
+		return DirectionLTR
+	}
+
+	return c.direction
+}
+
+// SetClock replaces the time source used for animations (time.Now by
+// default), e.g. to drive them deterministically.
+func (c *Context) SetClock(fn func() time.Time) {
+	c.clock = fn
+}
+
+// Now returns the time of the current frame. It is sampled once at the start
+// of Update, so every widget animates from the same instant.
+func (c *Context) Now() time.Time {
+	if c.now.IsZero() {
+		c.sampleClock()
+	}
+	return c.now
+}
+
+func (c *Context) sampleClock() {
+	if c.clock != nil {
+		c.now = c.clock()
+		return
+	}
+	c.now = time.Now()
+}
+
 // Root returns the root widget (typically a Layout).
 func (c *Context) Root() Layout {
 	return c.root
@@ -48,10 +114,94 @@ func (c *Context) SetIMEBridge(b IMEBridge) {
 	c.updateIMEForce(c.Focused())
 }
 
+// Screen returns the area of the last drawn frame, used to place dialogs
+// and popups.
+func (c *Context) Screen() image.Rectangle {
+	return c.screen
+}
+
+// ShowDialog opens d above the UI, over a dimmed backdrop. Until it is
+// closed, the widgets beneath are neither updated nor reachable by pointer
+// or keyboard, and Tab cycles within d. Dialogs stack: the last shown is
+// the active one.
+func (c *Context) ShowDialog(d ModalWidget) {
+	c.showModal(d, true)
+}
+
+// ShowPopup opens p like ShowDialog, without the backdrop (e.g. menus).
+// The popup handles clicks outside itself, usually by closing.
+func (c *Context) ShowPopup(p ModalWidget) {
+	c.showModal(p, false)
+}
+
+func (c *Context) showModal(d ModalWidget, backdrop bool) {
+	for _, m := range c.dialogs {
+		if m.w == d {
+			return
+		}
+	}
+
+	// The widgets beneath leave the tree: drop their transient state.
+	for _, w := range c.widgets {
+		w.SetHovered(false)
+		w.SetPressed(false)
+	}
+	c.capture = nil
+
+	// A dialog opened as another closes returns to where that one would.
+	prev := c.Focused()
+	if c.restoreFocus != nil {
+		prev, c.restoreFocus = c.restoreFocus, nil
+	}
+	c.dialogs = append(c.dialogs, modal{w: d, prevFocus: prev, backdrop: backdrop})
+}
+
+// ClosePopup removes a popup opened with ShowPopup.
+func (c *Context) ClosePopup(p ModalWidget) {
+	c.CloseDialog(p)
+}
+
+// CloseDialog removes d; the focus returns to the widget focused before d
+// was shown.
+func (c *Context) CloseDialog(d ModalWidget) {
+	for i, m := range c.dialogs {
+		if m.w != d {
+			continue
+		}
+
+		c.dialogs = append(c.dialogs[:i], c.dialogs[i+1:]...)
+		for _, w := range c.widgets {
+			w.SetHovered(false)
+			w.SetPressed(false)
+		}
+		c.capture = nil
+
+		if i == len(c.dialogs) {
+			c.restoreFocus = m.prevFocus
+		}
+		return
+	}
+}
+
+// ActiveDialog returns the topmost open dialog or popup, or nil.
+func (c *Context) ActiveDialog() ModalWidget {
+	if len(c.dialogs) == 0 {
+		return nil
+	}
+	return c.dialogs[len(c.dialogs)-1].w
+}
+
 func (c *Context) Add(w Widget) {
 	c.root.Add(w)
 }
 
+// FrameFocused returns the widget focused when the current Update started.
+// It differs from Focused once a widget moved the focus during the frame,
+// e.g. a TextInput dropping it on Enter.
+func (c *Context) FrameFocused() Widget {
+	return c.frameFocus
+}
+
 func (c *Context) Focused() Widget {
 	if c.focus < 0 || c.focus >= len(c.widgets) {
 		return nil
@@ -64,6 +214,7 @@ func (c *Context) Focused() Widget {
 // On desktop this is the mouse; on mobile this is the active touch.
 
 func (c *Context) rebuildWidgets() {
+	old := c.Focused()
 	c.widgets = c.widgets[:0]
 	var walk func(w Widget)
 	walk = func(w Widget) {
@@ -80,15 +231,50 @@ func (c *Context) rebuildWidgets() {
 		}
 	}
 
-	for _, w := range c.root.Children() {
-		walk(w)
+	// An open dialog replaces the whole tree, trapping the focus.
+	if d := c.ActiveDialog(); d != nil {
+		walk(d)
+	} else {
+		for _, w := range c.root.Children() {
+			walk(w)
+		}
+	}
+
+	// The tree may have changed (e.g. a Tabs page switch): keep the focus on
+	// the same widget, or drop it when the widget left the tree.
+	if old == nil {
+		return
 	}
+
+	for i, w := range c.widgets {
+		if w == old {
+			c.focus = i
+			return
+		}
+	}
+
+	c.focus = -1
+	old.SetFocused(false)
+	old.Dispatch(Event{Widget: old, Type: EventFocusLost})
+	c.updateIME(old, nil)
 }
 
 func (c *Context) Pointer() PointerStatus {
 	return *c.ptr
 }
 
+// CapturePointer makes w the hover target until the pointer is released,
+// even when the pointer leaves it (e.g. while dragging a slider thumb).
+// Call it from Update when the press starts.
+func (c *Context) CapturePointer(w Widget) {
+	c.capture = w
+}
+
+// PointerCapture returns the widget holding the pointer capture, or nil.
+func (c *Context) PointerCapture() Widget {
+	return c.capture
+}
+
 func (c *Context) SetFocus(w Widget) {
 	old := c.Focused()
 
@@ -274,12 +460,26 @@ func (c *Context) topmostAt(pos image.Point) Widget {
 }
 
 func (c *Context) Update() {
+	c.sampleClock()
+	c.frameFocus = c.Focused()
 	c.readPointerSnapshot()
-	c.root.Update(c)
+	if c.updateToasts() {
+		// The toast layer owns the pointer: widgets see it nowhere.
+		c.ptr.Position = image.Pt(-1<<20, -1<<20)
+		c.ptr.IsDown, c.ptr.IsJustDown, c.ptr.IsJustUp = false, false, false
+	}
+
+	if d := c.ActiveDialog(); d != nil {
+		d.Update(c)
+	} else {
+		c.root.Update(c)
+	}
 
 	c.rebuildWidgets()
+	c.resolveFocus()
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+	// Ctrl+Tab is left to widgets (e.g. Tabs page switching).
+	if inpututil.IsKeyJustPressed(ebiten.KeyTab) && !ebiten.IsKeyPressed(ebiten.KeyControl) {
 		if ebiten.IsKeyPressed(ebiten.KeyShift) {
 			c.focusPrev()
 		} else {
@@ -292,13 +492,15 @@ func (c *Context) Update() {
 		target = c.topmostAt(c.ptr.Position)
 		if target != nil && target.Focusable() && target.IsEnabled() {
 			c.SetFocus(target)
-		} else {
+		} else if c.ActiveDialog() == nil {
 			c.SetFocus(nil)
 		}
 	}
 
 	var hoverTarget Widget
-	if !c.ptr.IsTouch {
+	if c.capture != nil {
+		hoverTarget = c.capture
+	} else if !c.ptr.IsTouch {
 		hoverTarget = c.topmostAt(c.ptr.Position)
 	}
 
@@ -331,6 +533,10 @@ func (c *Context) Update() {
 
 		w.SetFocused((c.Focused() == w) && w.IsEnabled() && w.Focusable())
 	}
+
+	if c.ptr.IsJustUp {
+		c.capture = nil
+	}
 }
 
 func (c *Context) Draw(dst *ebiten.Image) {
@@ -338,8 +544,40 @@ func (c *Context) Draw(dst *ebiten.Image) {
 		return
 	}
 
+	c.screen = dst.Bounds()
 	c.root.SetHeight(dst.Bounds().Dy())
 	c.root.SetFrame(0, 0, dst.Bounds().Dx())
 	c.root.Draw(c, dst)
 	c.root.DrawOverlay(c, dst)
+
+	for _, m := range c.dialogs {
+		if m.backdrop {
+			drawRoundedRect(dst, c.screen, 0, c.theme.BackdropColor)
+		}
+		m.w.Draw(c, dst)
+		m.w.DrawOverlay(c, dst)
+	}
+
+	c.drawToasts(dst)
+}
+
+// resolveFocus restores the focus after a dialog closed, and keeps it
+// inside the active dialog.
+func (c *Context) resolveFocus() {
+	if w := c.restoreFocus; w != nil {
+		c.restoreFocus = nil
+		c.SetFocus(w)
+	}
+
+	d := c.ActiveDialog()
+	if d == nil || c.Focused() != nil {
+		return
+	}
+
+	if w := d.InitialFocus(); w != nil {
+		c.SetFocus(w)
+	}
+	if c.Focused() == nil {
+		c.focusNext()
+	}
 }
@@ -2,9 +2,13 @@ package demo
 
 import (
 	"fmt"
+	"image"
+	"image/color"
 	"strings"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/tinne26/etxt"
 
 	"github.com/erparts/go-uikit"
@@ -15,6 +19,7 @@ import (
 type Game struct {
 	stack *layout.Stack
 	grid  *layout.Grid
+	tabs  *widget.Tabs
 	ime   uikit.IMEBridge
 
 	theme *uikit.Theme
@@ -26,12 +31,29 @@ type Game struct {
 	txtDis       *widget.TextInput
 	ta           *widget.TextArea
 	sel          *widget.Select
+	combo        *widget.ComboBox
+	slider       *widget.Slider
+	rangeSlider  *widget.Slider
+	radio        *widget.RadioGroup[string]
+	sw           *widget.Switch
+	chkAll       *widget.Checkbox
+	progress     *widget.ProgressBar
+	spinner      *widget.Spinner
+	chkItems     []*widget.Checkbox
 	box          *widget.Container
 	chkA         *widget.Checkbox
 	chkDis       *widget.Checkbox
-	chkGrid      *widget.Checkbox
+	chkRTL       *widget.Checkbox
 	btnA         *widget.Button
 	btnDis       *widget.Button
+	btnDialog    *widget.Button
+	btnMenu      *widget.Button
+	btnBold      *widget.Button
+	align        *widget.ButtonGroup[string]
+	date         *widget.DatePicker
+	clock        *widget.TimePicker
+	menu         *widget.Menu
+	menuBar      *widget.MenuBar
 	focusInfo    *widget.Label
 	exampleLabel *widget.Label
 
@@ -63,7 +85,6 @@ func (g *Game) initOnce() {
 	g.stack = layout.NewStack(g.theme)
 
 	g.grid = layout.NewGrid(g.theme)
-	g.grid.SetVisible(false)
 
 	g.title = widget.NewLabel(g.theme, "")
 	g.title.SetTextFunc(func() string {
@@ -102,12 +123,25 @@ func (g *Game) initOnce() {
 	g.ta = widget.NewTextArea(g.theme, "Multi-line…")
 	g.ta.SetLines(5)
 	g.ta.SetText("Line 1\nLine 2\nLine 3\nLine 4\nLine 5\nLine 6\nLine 7")
+	g.ta.SetHighlighter(widget.HighlighterFunc(func(_ int, line string) []widget.Span {
+		// Digits are drawn in the focus colour.
+		var spans []widget.Span
+		for i, r := range line {
+			if r >= '0' && r <= '9' {
+				spans = append(spans, widget.Span{Start: i, End: i + 1, Style: widget.TextStyle{Color: g.theme.FocusColor}})
+			}
+		}
+		return spans
+	}))
+	g.ta.ShowGutter = true
+	g.ta.HighlightCurrentLine = true
+	g.ta.SetMarker(2, widget.GutterMarker{Kind: widget.MarkerWarning, Message: "Example warning"})
 
 	g.sel = widget.NewSelect(g.theme, nil)
 	g.sel.SetOptions([]widget.SelectOption{
-		{0, "Select a value..."},
-		{1, "Option A"}, {2, "Option B"}, {3, "Option C"},
-		{4, "Option D"}, {5, "Option E"}, {6, "Option F"},
+		{Value: 0, Label: "Select a value..."},
+		{Value: 1, Label: "Option A"}, {Value: 2, Label: "Option B"}, {Value: 3, Label: "Option C"},
+		{Value: 4, Label: "Option D"}, {Value: 5, Label: "Option E"}, {Value: 6, Label: "Option F"},
 	})
 
 	g.sel.On(uikit.EventValueChange, func(e uikit.Event) bool {
@@ -121,6 +155,51 @@ func (g *Game) initOnce() {
 		return false
 	}, false)
 
+	g.combo = widget.NewComboBox(g.theme, "Fruit (fuzzy)…", widget.FuzzySuggestions([]widget.SelectOption{
+		{Value: "apple", Label: "Apple"}, {Value: "apricot", Label: "Apricot"},
+		{Value: "banana", Label: "Banana"}, {Value: "blackberry", Label: "Blackberry"},
+		{Value: "cherry", Label: "Cherry"}, {Value: "grape", Label: "Grape"},
+		{Value: "pineapple", Label: "Pineapple"}, {Value: "strawberry", Label: "Strawberry"},
+	}))
+
+	g.slider = widget.NewSlider(g.theme, 0, 100)
+	g.slider.SetStep(5)
+	g.slider.SetValue(40)
+	g.slider.TickStep = 10
+	g.slider.ShowValue = true
+
+	g.progress = widget.NewProgressBar(g.theme)
+	g.progress.ShowText = true
+	g.progress.SetValue(g.slider.Value() / 100)
+	g.slider.On(uikit.EventValueChange, func(e uikit.Event) bool {
+		g.progress.SetValue(g.slider.Value() / 100)
+		return false
+	}, false)
+
+	g.spinner = widget.NewSpinner(g.theme, "Loading…")
+
+	g.rangeSlider = widget.NewRangeSlider(g.theme, 0, 1000)
+	g.rangeSlider.SetStep(50)
+	g.rangeSlider.SetRange(200, 750)
+	g.rangeSlider.ShowValue = true
+
+	g.radio = widget.NewRadioGroup(g.theme, []widget.RadioOption[string]{
+		{Value: "s", Label: "Small"}, {Value: "m", Label: "Medium"}, {Value: "l", Label: "Large"},
+	})
+	g.radio.SetOrientation(widget.Horizontal)
+	g.radio.SetValue("m")
+
+	g.sw = widget.NewSwitch(g.theme, "Notifications")
+	g.sw.LabelLeading = true
+	g.sw.SetOn(true)
+
+	g.chkAll = widget.NewCheckbox(g.theme, "All toppings")
+	for _, name := range []string{"Cheese", "Olives", "Basil"} {
+		g.chkItems = append(g.chkItems, widget.NewCheckbox(g.theme, name))
+	}
+	g.chkItems[0].SetChecked(true)
+	widget.NewCheckboxGroup(g.chkAll, g.chkItems...)
+
 	g.box = widget.NewContainer(g.theme)
 	g.box.SetHeight(140)
 	g.box.OnDraw = func(ctx *uikit.Context, dst *ebiten.Image) {
@@ -150,34 +229,202 @@ func (g *Game) initOnce() {
 	g.chkDis.SetChecked(true)
 	g.chkDis.SetEnabled(false)
 
-	g.chkGrid = widget.NewCheckbox(g.theme, "Use grid layout")
-	g.chkGrid.On(uikit.EventValueChange, func(e uikit.Event) bool {
+	g.chkRTL = widget.NewCheckbox(g.theme, "Right-to-left layout")
+	g.chkRTL.On(uikit.EventValueChange, func(e uikit.Event) bool {
 		if e.Widget.(*widget.Checkbox).Checked() {
-			g.grid.SetVisible(true)
-			g.stack.SetVisible(false)
+			g.ctx.SetDirection(uikit.DirectionRTL)
 			return false
 		}
 
-		g.stack.SetVisible(true)
-		g.grid.SetVisible(false)
+		g.ctx.SetDirection(uikit.DirectionLTR)
 		return false
 	}, false)
 
 	g.btnA = widget.NewButton(g.theme, "Action (enabled)")
+	g.btnA.Variant = widget.ButtonPrimary
+	g.btnA.Form = g.stack // Enter in the text fields clicks it
 	g.btnA.On(uikit.EventClick, func(_ uikit.Event) bool {
 		g.clickCount++
+		g.ctx.Notify(fmt.Sprintf("Clicked %d times", g.clickCount), uikit.NotifyInfo, 3*time.Second).
+			SetAction("Undo", func() { g.clickCount-- })
 		return false
 	}, false)
 
 	g.btnDis = widget.NewButton(g.theme, "Action (disabled)")
+	g.btnDis.Variant = widget.ButtonDanger
 	g.btnDis.SetEnabled(false)
 
+	g.btnBold = widget.NewToggleButton(g.theme, "Bold")
+	g.btnBold.Variant = widget.ButtonGhost
+
+	g.align = widget.NewButtonGroup(g.theme, []widget.RadioOption[string]{
+		{Value: "start", Label: "Start"}, {Value: "center", Label: "Center"}, {Value: "end", Label: "End"},
+	})
+	g.align.SetValue("start")
+
+	g.date = widget.NewDatePicker(g.theme)
+	g.date.WeekStart = widget.WeekStartFor("en-US")
+	g.date.Min = time.Now()
+	g.date.Max = time.Now().AddDate(1, 0, 0)
+	g.date.Disabled = func(d time.Time) bool { return d.Weekday() == time.Saturday || d.Weekday() == time.Sunday }
+
+	g.clock = widget.NewTimePicker(g.theme)
+	g.clock.Use12Hour = true
+	g.clock.MinuteStep = 15
+	g.clock.Min, g.clock.Max = 9*time.Hour, 17*time.Hour
+	g.clock.SetClock(9, 0)
+
+	g.btnDialog = widget.NewButton(g.theme, "Open dialog…")
+	g.btnDialog.OnClick = func() {
+		widget.Prompt(g.ctx, "Greeting", "What is your name?", "", func(name string, ok bool) {
+			if !ok || name == "" {
+				return
+			}
+
+			widget.Confirm(g.ctx, "Greeting", fmt.Sprintf("Reset the click count, %s?", name), func(ok bool) {
+				if ok {
+					g.clickCount = 0
+					g.ctx.Notify("Click count reset", uikit.NotifySuccess, 2*time.Second)
+				}
+			})
+		})
+	}
+
+	g.menu = widget.NewMenu(g.theme)
+	g.menu.AddItem("Reset clicks", func() { g.clickCount = 0 }).Accel = "Ctrl+R"
+	g.menu.Add(&widget.MenuItem{Label: "Paste", Accel: "Ctrl+V", Disabled: true})
+	g.menu.AddSeparator()
+	rtl := &widget.MenuItem{Label: "Right-to-left", Checkable: true}
+	rtl.OnSelect = func(it *widget.MenuItem) { g.chkRTL.SetChecked(it.Checked) }
+	g.menu.Add(rtl)
+	corner := g.menu.AddSubmenu("Toast corner")
+	for _, c := range []struct {
+		label  string
+		corner uikit.ToastCorner
+	}{
+		{"Bottom right", uikit.ToastBottomRight}, {"Bottom left", uikit.ToastBottomLeft},
+		{"Top right", uikit.ToastTopRight}, {"Top left", uikit.ToastTopLeft},
+	} {
+		corner.AddItem(c.label, func() {
+			g.ctx.SetToastCorner(c.corner)
+			g.ctx.Notify(c.label, uikit.NotifyInfo, 2*time.Second)
+		})
+	}
+	g.chkRTL.On(uikit.EventValueChange, func(e uikit.Event) bool {
+		rtl.Checked = g.chkRTL.Checked()
+		return false
+	}, false)
+
+	g.menuBar = widget.NewMenuBar(g.theme)
+	file := g.menuBar.AddMenu("&File")
+	file.AddItem("&New tab", func() {
+		page := layout.NewStack(g.theme)
+		page.SetPadding(g.theme.SpaceS, g.theme.SpaceS)
+		page.Add(widget.NewTextInput(g.theme, "Scratch…"))
+		i := g.tabs.AddTab(fmt.Sprintf("Tab %d", g.tabs.TabCount()+1), page)
+		g.tabs.SetClosable(i, true)
+		g.tabs.SetActive(i)
+	}).Accel = "Ctrl+T"
+	file.AddSeparator()
+	file.Add(&widget.MenuItem{Label: "&Quit", Disabled: true})
+	edit := g.menuBar.AddMenu("&Edit")
+	edit.AddItem("&Undo", func() { g.ta.Undo() }).Accel = "Ctrl+Z"
+	edit.AddItem("&Redo", func() { g.ta.Redo() }).Accel = "Ctrl+Y"
+	edit.AddSeparator()
+	edit.AddItem("&Find…", func() { g.ta.OpenFind(false) }).Accel = "Ctrl+F"
+	view := g.menuBar.AddMenu("&View")
+	view.Add(rtl)
+	view.Add(&widget.MenuItem{Label: "&Toast corner", Submenu: corner})
+
+	g.btnMenu = widget.NewButton(g.theme, "Menu… (or right-click)")
+	g.btnMenu.OnClick = func() { g.menu.OpenFor(g.ctx, g.btnMenu) }
+	g.theme.SetIcon("menu", uikit.NewIcon(demoMenuIcon(), true))
+	g.btnMenu.SetIcon(g.theme.Icon("menu"))
+
+	g.ctx.Add(g.menuBar)
 	g.ctx.Add(g.title)
 	g.ctx.Add(g.focusInfo)
-	g.ctx.Add(g.chkGrid)
+	g.ctx.Add(g.chkRTL)
+
+	g.tabs = widget.NewTabs(g.theme)
+	g.tabs.AddTab("Stack layout", g.stack)
+	g.tabs.AddLazyTab("Grid layout", func() uikit.Layout { return g.grid })
+	g.tabs.AddLazyTab("Tree", func() uikit.Layout {
+		page := layout.NewStack(g.theme)
+		page.SetPadding(g.theme.SpaceS, g.theme.SpaceS)
+		info := widget.NewLabel(g.theme, "Multi selection; type to search.")
+		tree := widget.NewTree[string](g.theme, demoTree{})
+		tree.SetSelectionMode(widget.SelectMulti)
+		tree.SetHeight(g.theme.ControlH * 10)
+		tree.OnActivate = func(node string) {
+			g.ctx.Notify("Activated node "+node, uikit.NotifyInfo, 2*time.Second)
+		}
+		tree.On(uikit.EventValueChange, func(uikit.Event) bool {
+			info.SetText(fmt.Sprintf("%d selected", len(tree.Selected())))
+			return false
+		}, false)
+		page.Add(info, tree)
+		return page
+	})
+	g.tabs.AddLazyTab("List", func() uikit.Layout {
+		page := layout.NewStack(g.theme)
+		page.SetPadding(g.theme.SpaceS, g.theme.SpaceS)
+		list := widget.NewListView(g.theme, &demoList{theme: g.theme, n: 10000})
+		list.SetSelectionMode(widget.SelectMulti)
+		list.SetHeight(g.theme.ControlH * 10)
+		last := widget.NewButton(g.theme, "Scroll to last")
+		last.OnClick = func() { list.ScrollToIndex(list.Source().Len() - 1) }
+		page.Add(list, last)
+		return page
+	})
+	g.tabs.AddLazyTab("Table", func() uikit.Layout {
+		page := layout.NewStack(g.theme)
+		page.SetPadding(g.theme.SpaceS, g.theme.SpaceS)
+		table := newDemoTable(g.theme)
+		table.SetHeight(g.theme.ControlH * 12)
+		page.Add(table)
+		return page
+	})
+	g.tabs.AddLazyTab("Colours", func() uikit.Layout {
+		page := layout.NewStack(g.theme)
+		page.SetPadding(g.theme.SpaceS, g.theme.SpaceS)
+		info := widget.NewLabel(g.theme, "Picks the accent colour; the compact picker opens in a popup.")
+		swatches := []color.RGBA{
+			{231, 76, 60, 255}, {230, 126, 34, 255}, {241, 196, 15, 255}, {46, 204, 113, 255},
+			{26, 188, 156, 255}, {52, 152, 219, 255}, {155, 89, 182, 255}, {128, 128, 128, 128},
+		}
 
-	g.ctx.Add(g.stack)
-	g.ctx.Add(g.grid)
+		full := widget.NewColorPicker(g.theme, g.theme.FocusColor)
+		full.ShowAlpha = true
+		full.Swatches = swatches
+
+		compact := widget.NewColorPicker(g.theme, g.theme.FocusColor)
+		compact.Compact = true
+		compact.Swatches = swatches
+
+		for _, p := range []*widget.ColorPicker{full, compact} {
+			p.On(uikit.EventValueChange, func(e uikit.Event) bool {
+				c := e.Value.(color.RGBA)
+				g.theme.FocusColor = c
+				full.SetColor(c)
+				compact.SetColor(c)
+				return false
+			}, false)
+		}
+		page.Add(info, compact, full)
+		return page
+	})
+	about := g.tabs.AddLazyTab("About", func() uikit.Layout {
+		page := layout.NewStack(g.theme)
+		page.SetPadding(g.theme.SpaceS, g.theme.SpaceS)
+		page.Add(widget.NewLabel(g.theme, "Only the active tab page is updated, drawn and focusable. Ctrl+Tab switches tabs."))
+		picture := widget.NewImage(g.theme, demoPicture(320, 120))
+		picture.Rounded = true
+		page.Add(picture)
+		return page
+	})
+	g.tabs.SetClosable(about, true)
+	g.ctx.Add(g.tabs)
 
 	contentWidgets := []uikit.Widget{
 		g.exampleLabel,
@@ -186,11 +433,28 @@ func (g *Game) initOnce() {
 		g.txtDis,
 		g.ta,
 		g.sel,
+		g.combo,
+		g.slider,
+		g.progress,
+		g.spinner,
+		g.rangeSlider,
+		g.radio,
+		g.sw,
+		g.chkAll,
+		g.chkItems[0],
+		g.chkItems[1],
+		g.chkItems[2],
 		g.box,
 		g.chkA,
 		g.chkDis,
 		g.btnA,
 		g.btnDis,
+		g.btnBold,
+		g.align,
+		g.date,
+		g.clock,
+		g.btnDialog,
+		g.btnMenu,
 	}
 
 	g.stack.SetChildren(contentWidgets)
@@ -198,6 +462,10 @@ func (g *Game) initOnce() {
 }
 
 func (g *Game) Update() error {
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) && g.ctx.ActiveDialog() == nil {
+		g.menu.OpenAt(g.ctx, image.Pt(ebiten.CursorPosition()))
+	}
+
 	g.ctx.Update()
 	return nil
 }
@@ -0,0 +1,37 @@
+package demo
+
+import (
+	"image"
+	"image/color"
+)
+
+// demoPicture generates a colour gradient to show in an Image widget.
+func demoPicture(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			img.SetRGBA(x, y, color.RGBA{
+				R: uint8(255 * x / w),
+				G: uint8(255 * y / h),
+				B: uint8(255 - 255*x/w),
+				A: 255,
+			})
+		}
+	}
+	return img
+}
+
+// demoMenuIcon generates a "hamburger" mask icon: three white bars.
+func demoMenuIcon() image.Image {
+	const size = 24
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	white := color.RGBA{255, 255, 255, 255}
+	for _, y0 := range []int{4, 10, 16} {
+		for y := y0; y < y0+4; y++ {
+			for x := 2; x < size-2; x++ {
+				img.SetRGBA(x, y, white)
+			}
+		}
+	}
+	return img
+}
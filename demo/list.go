@@ -0,0 +1,22 @@
+package demo
+
+import (
+	"fmt"
+
+	"github.com/erparts/go-uikit"
+	"github.com/erparts/go-uikit/widget"
+)
+
+// demoList is a ListSource of n numbered label rows.
+type demoList struct {
+	theme *uikit.Theme
+	n     int
+}
+
+func (l *demoList) Len() int { return l.n }
+
+func (l *demoList) NewRow() uikit.Widget { return widget.NewLabel(l.theme, "") }
+
+func (l *demoList) BindRow(row uikit.Widget, i int) {
+	row.(*widget.Label).SetText(fmt.Sprintf("Item %d", i+1))
+}
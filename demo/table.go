@@ -0,0 +1,49 @@
+package demo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/erparts/go-uikit"
+	"github.com/erparts/go-uikit/widget"
+)
+
+// demoLogEntry is a row of the table demo.
+type demoLogEntry struct {
+	Device string
+	Time   time.Time
+	Level  string
+	Temp   float64
+	Volts  [8]float64
+}
+
+func newDemoTable(theme *uikit.Theme) *widget.Table[demoLogEntry] {
+	levels := []string{"info", "warning", "error"}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rows := make([]demoLogEntry, 5000)
+	for i := range rows {
+		e := &rows[i]
+		e.Device = fmt.Sprintf("dev-%03d", (i*37)%250)
+		e.Time = start.Add(time.Duration(i*7919%5000) * time.Minute)
+		e.Level = levels[(i*13)%len(levels)]
+		e.Temp = 20 + float64((i*31)%400)/10
+		for k := range e.Volts {
+			e.Volts[k] = float64((i+k*97)%500) / 100
+		}
+	}
+
+	t := widget.NewTable[demoLogEntry](theme)
+	t.FreezeFirst = true
+	t.SetSelectionMode(widget.SelectMulti)
+	t.AddColumn(widget.TextColumn("Device", func(e demoLogEntry) string { return e.Device }))
+	t.AddColumn(widget.TimeColumn("Time", "2006-01-02 15:04", func(e demoLogEntry) time.Time { return e.Time }))
+	t.AddColumn(widget.TextColumn("Level", func(e demoLogEntry) string { return e.Level }))
+	t.AddColumn(widget.NumberColumn("Temp °C", "%.1f", func(e demoLogEntry) float64 { return e.Temp }))
+	for k := range 8 {
+		t.AddColumn(widget.NumberColumn(fmt.Sprintf("V%d", k+1), "%.2f", func(e demoLogEntry) float64 { return e.Volts[k] }))
+	}
+	t.SetRows(rows)
+
+	return t
+}
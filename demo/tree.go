@@ -0,0 +1,34 @@
+package demo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// demoTree is a generated hierarchy: 100 roots with 1000 children each,
+// and three leaves under every child. Nodes are dotted paths ("4.17.2").
+type demoTree struct{}
+
+func (demoTree) Roots() []string { return demoRange("", 100) }
+
+func (demoTree) Children(node string) []string {
+	switch strings.Count(node, ".") {
+	case 0:
+		return demoRange(node+".", 1000)
+	case 1:
+		return demoRange(node+".", 3)
+	}
+	return nil
+}
+
+func (demoTree) HasChildren(node string) bool { return strings.Count(node, ".") < 2 }
+
+func (demoTree) Label(node string) string { return "Node " + node }
+
+func demoRange(prefix string, n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = fmt.Sprintf("%s%d", prefix, i+1)
+	}
+	return out
+}
@@ -0,0 +1,14 @@
+package uikit
+
+// Direction is the base text direction used to lay out text and mirror widgets.
+type Direction int
+
+const (
+	// DirectionInherit makes a widget use the Context direction.
+	DirectionInherit Direction = iota
+	// DirectionLTR lays out text left-to-right.
+	DirectionLTR
+	// DirectionRTL lays out text right-to-left (Arabic, Hebrew...) and mirrors
+	// widget layouts (checkbox box on the right, Select chevron on the left...).
+	DirectionRTL
+)
@@ -43,6 +43,9 @@ type Event struct {
 	Type    EventType
 	Pointer *PointerStatus
 	Key     ebiten.Key
+	// Value carries the new value of EventValueChange for widgets holding a
+	// single typed value (e.g. the selected RadioGroup option). May be nil.
+	Value any
 }
 
 // EventHandler is a function invoked when an event is dispatched.
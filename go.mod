@@ -5,8 +5,10 @@ go 1.24.0
 require (
 	github.com/erparts/go-shapes v0.0.0-20251211181419-8d4b776c77b9
 	github.com/hajimehoshi/ebiten/v2 v2.9.7
+	github.com/rivo/uniseg v0.4.7
 	github.com/tinne26/etxt v0.0.9
 	golang.org/x/image v0.31.0
+	golang.org/x/text v0.29.0
 )
 
 require (
@@ -16,5 +18,4 @@ require (
 	github.com/jezek/xgb v1.1.1 // indirect
 	golang.org/x/sync v0.17.0 // indirect
 	golang.org/x/sys v0.36.0 // indirect
-	golang.org/x/text v0.29.0 // indirect
 )
@@ -0,0 +1,128 @@
+package uikit
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/tinne26/etxt"
+	"golang.org/x/image/font/sfnt"
+)
+
+// Names of the theme icons used by the widgets. When one is not registered
+// the widget falls back to its built-in drawing.
+const (
+	IconCheck       = "check"        // Checkbox and checkable menu items
+	IconChevronDown = "chevron-down" // Select
+)
+
+// Icon is a small image shown next to a label (buttons, select options,
+// menu items).
+// - Mask icons are white shapes on a transparent background; they are drawn
+// in the colour of the text next to them, so they follow hover and
+// disabled states.
+// - Other icons keep their own colours.
+type Icon struct {
+	Image *ebiten.Image
+	Mask  bool
+}
+
+// NewIcon wraps img, converting it to an *ebiten.Image if needed.
+func NewIcon(img image.Image, mask bool) *Icon {
+	return &Icon{Image: EbitenImage(img), Mask: mask}
+}
+
+// EbitenImage returns img as an *ebiten.Image, uploading it to the GPU
+// unless it already is one.
+func EbitenImage(img image.Image) *ebiten.Image {
+	if img == nil {
+		return nil
+	}
+	if e, ok := img.(*ebiten.Image); ok {
+		return e
+	}
+	return ebiten.NewImageFromImage(img)
+}
+
+// Draw draws the icon scaled to fit r, keeping its aspect ratio, and
+// centred. Mask icons are drawn in col.
+func (ic *Icon) Draw(dst *ebiten.Image, r image.Rectangle, col color.RGBA) {
+	if ic == nil || ic.Image == nil || r.Empty() {
+		return
+	}
+
+	b := ic.Image.Bounds()
+	if b.Empty() {
+		return
+	}
+
+	s := min(float64(r.Dx())/float64(b.Dx()), float64(r.Dy())/float64(b.Dy()))
+	op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+	op.GeoM.Scale(s, s)
+	op.GeoM.Translate(
+		float64(r.Min.X)+(float64(r.Dx())-float64(b.Dx())*s)/2,
+		float64(r.Min.Y)+(float64(r.Dy())-float64(b.Dy())*s)/2,
+	)
+	if ic.Mask {
+		op.ColorScale.ScaleWithColor(col)
+	}
+	dst.DrawImage(ic.Image, op)
+}
+
+// Icon returns the icon registered under name, or nil.
+func (t *Theme) Icon(name string) *Icon {
+	return t.icons[name]
+}
+
+// SetIcon registers icon under name; nil removes it.
+func (t *Theme) SetIcon(name string, icon *Icon) {
+	if icon == nil {
+		delete(t.icons, name)
+		return
+	}
+	if t.icons == nil {
+		t.icons = make(map[string]*Icon)
+	}
+	t.icons[name] = icon
+}
+
+// LoadIconAtlas registers the icons of a sprite sheet. The JSON index maps
+// icon names to their rectangle in the sheet:
+//
+//	{"save": {"x": 0, "y": 0, "w": 16, "h": 16}, ...}
+func (t *Theme) LoadIconAtlas(sheet image.Image, index io.Reader, mask bool) error {
+	var frames map[string]struct{ X, Y, W, H int }
+	if err := json.NewDecoder(index).Decode(&frames); err != nil {
+		return fmt.Errorf("uikit: icon atlas index: %w", err)
+	}
+
+	img := EbitenImage(sheet)
+	for name, f := range frames {
+		r := image.Rect(f.X, f.Y, f.X+f.W, f.Y+f.H)
+		if r.Empty() || !r.In(img.Bounds()) {
+			return fmt.Errorf("uikit: icon %q is outside the atlas", name)
+		}
+		t.SetIcon(name, &Icon{Image: img.SubImage(r).(*ebiten.Image), Mask: mask})
+	}
+	return nil
+}
+
+// LoadIconFont registers glyphs of an icon font (name to code point) as
+// mask icons, rendered at IconSize.
+func (t *Theme) LoadIconFont(font *sfnt.Font, glyphs map[string]rune) {
+	r := etxt.NewRenderer()
+	r.SetFont(font)
+	r.SetSize(float64(t.IconSize))
+	r.SetColor(color.RGBA{255, 255, 255, 255})
+	r.SetAlign(etxt.Center)
+
+	size := t.IconSize
+	for name, g := range glyphs {
+		img := ebiten.NewImage(size, size)
+		r.Draw(img, string(g), size/2, size/2)
+		t.SetIcon(name, &Icon{Image: img, Mask: true})
+	}
+}
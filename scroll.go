@@ -110,6 +110,17 @@ func (s *Scroller) Clamp(viewportH int, contentH int) {
 	}
 }
 
+// Reveal scrolls the least amount needed to show the content span
+// [top, bottom) in a viewport of height viewportH.
+func (s *Scroller) Reveal(top, bottom, viewportH int) {
+	switch {
+	case top < s.ScrollY:
+		s.ScrollY = top
+	case bottom > s.ScrollY+viewportH:
+		s.ScrollY = bottom - viewportH
+	}
+}
+
 // DrawBar draws a simple vertical scrollbar inside a clipped target (dst should already be a SubImage of the viewport).
 // viewportW/H should match dst's size.
 func (s *Scroller) DrawBar(dst *ebiten.Image, theme *Theme, viewportW, viewportH, contentH int) {
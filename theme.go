@@ -32,6 +32,7 @@ type Theme struct {
 	SpaceL int
 
 	CheckSize int // checkbox square size
+	IconSize  int // icons in buttons, options and menu items
 
 	// Validation
 	ErrorFontPx int
@@ -49,8 +50,15 @@ type Theme struct {
 	DisabledColor       color.RGBA
 	ErrorTextColor      color.RGBA
 	ErrorBorderColor    color.RGBA
+	InfoColor           color.RGBA
+	SuccessColor        color.RGBA
+	WarningColor        color.RGBA
+	ErrorColor          color.RGBA
 	Scrollbar           color.RGBA
 	CaretColor          color.RGBA
+	SelectionColor      color.RGBA
+	MatchColor          color.RGBA // search matches
+	BackdropColor       color.RGBA // dims the UI behind modal dialogs
 
 	// Scrollbar
 	ScrollbarRadius int
@@ -62,7 +70,11 @@ type Theme struct {
 	CaretBlink    time.Duration
 	CaretMarginPx int
 
+	// Animation length of state transitions (switch knob, toasts...).
+	AnimDuration time.Duration
+
 	renderer *etxt.Renderer
+	icons    map[string]*Icon
 }
 
 func (t *Theme) Text() *etxt.Renderer {
@@ -193,6 +205,7 @@ func NewTheme(font *sfnt.Font, fontPx int) *Theme {
 		SpaceL: spaceL,
 
 		CheckSize: checkSize,
+		IconSize:  checkSize,
 
 		ErrorFontPx: errorFontPx,
 		ErrorGap:    errorGap,
@@ -208,10 +221,20 @@ func NewTheme(font *sfnt.Font, fontPx int) *Theme {
 		DisabledColor:       color.RGBA{90, 96, 106, 255},
 		ErrorTextColor:      color.RGBA{235, 110, 110, 255},
 		ErrorBorderColor:    color.RGBA{235, 110, 110, 255},
+		InfoColor:           color.RGBA{120, 170, 255, 255},
+		SuccessColor:        color.RGBA{110, 200, 130, 255},
+		WarningColor:        color.RGBA{230, 180, 80, 255},
+		ErrorColor:          color.RGBA{235, 110, 110, 255},
+
+		SelectionColor: color.RGBA{48, 68, 102, 102},
+		MatchColor:     color.RGBA{110, 92, 28, 110},
+		BackdropColor:  color.RGBA{0, 0, 0, 150},
 
 		CaretColor:    color.RGBA{235, 238, 242, 255},
 		CaretWidthPx:  2,
 		CaretBlink:    600 * time.Millisecond,
 		CaretMarginPx: 0,
+
+		AnimDuration: 150 * time.Millisecond,
 	}
 }
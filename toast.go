@@ -0,0 +1,353 @@
+package uikit
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/erparts/go-uikit/common"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/tinne26/etxt"
+)
+
+// NotifyLevel selects the accent colour of a toast.
+type NotifyLevel int
+
+const (
+	NotifyInfo NotifyLevel = iota
+	NotifySuccess
+	NotifyWarning
+	NotifyError
+)
+
+// ToastCorner is the screen corner toasts stack from.
+type ToastCorner int
+
+const (
+	ToastBottomRight ToastCorner = iota
+	ToastBottomLeft
+	ToastTopRight
+	ToastTopLeft
+)
+
+// maxToasts is the number of toasts on screen; the others wait in the queue.
+const maxToasts = 4
+
+// Toast is a short notification queued with Context.Notify.
+// - It slides in at the Context toast corner, above everything else.
+// - It is dismissed after its duration (paused while hovered), by a click,
+// or by swiping it sideways.
+// - It may carry one action button (e.g. "Undo").
+type Toast struct {
+	message  string
+	level    NotifyLevel
+	duration time.Duration
+
+	actionLabel string
+	action      func()
+
+	visible   bool
+	elapsed   time.Duration // time on screen, paused while hovered
+	anim      float64       // slide-in progress, 0 to 1
+	dismissed bool
+
+	swipe  int // horizontal drag offset
+	pressX int
+	rect   image.Rectangle // last layout, slide offset included
+}
+
+// SetAction adds a button to the toast; clicking it calls fn and dismisses
+// the toast.
+func (t *Toast) SetAction(label string, fn func()) *Toast {
+	t.actionLabel, t.action = label, fn
+	return t
+}
+
+// Dismiss hides the toast (or drops it from the queue).
+func (t *Toast) Dismiss() {
+	t.dismissed = true
+}
+
+func (t *Toast) Message() string    { return t.message }
+func (t *Toast) Level() NotifyLevel { return t.level }
+func (t *Toast) IsDismissed() bool  { return t.dismissed }
+func (t *Toast) hasAction() bool    { return t.actionLabel != "" && t.action != nil }
+func (t *Toast) drawRect() image.Rectangle {
+	return t.rect.Add(image.Pt(t.swipe, 0))
+}
+
+// Notify queues a toast showing msg for d. A zero duration keeps it until
+// it is dismissed.
+func (c *Context) Notify(msg string, level NotifyLevel, d time.Duration) *Toast {
+	t := &Toast{message: msg, level: level, duration: d}
+	c.toasts = append(c.toasts, t)
+	return t
+}
+
+// SetToastCorner sets the screen corner toasts stack from (bottom right by
+// default).
+func (c *Context) SetToastCorner(corner ToastCorner) {
+	c.toastCorner = corner
+}
+
+func (c *Context) levelColor(l NotifyLevel) color.RGBA {
+	switch l {
+	case NotifySuccess:
+		return c.theme.SuccessColor
+	case NotifyWarning:
+		return c.theme.WarningColor
+	case NotifyError:
+		return c.theme.ErrorColor
+	default:
+		return c.theme.InfoColor
+	}
+}
+
+func (c *Context) toastSize() (int, int) {
+	w := min(c.screen.Dx()-c.theme.SpaceM*2, c.theme.ControlH*12)
+	return max(w, 0), c.theme.ControlH + c.theme.SpaceS*2
+}
+
+// toastAction returns the action button of a toast laid out at r.
+func (c *Context) toastAction(t *Toast, r image.Rectangle) image.Rectangle {
+	if !t.hasAction() {
+		return image.Rectangle{}
+	}
+
+	th := c.theme
+	w := th.Text().Measure(t.actionLabel).IntWidth() + th.PadX*2
+	y := r.Min.Y + (r.Dy()-th.ControlH)/2
+	x := r.Max.X - th.SpaceS - w
+	if c.Direction() == DirectionRTL {
+		x = r.Min.X + th.SpaceS
+	}
+	return image.Rect(x, y, x+w, y+th.ControlH)
+}
+
+// slideOffset moves a toast towards the screen edge it enters from.
+func (c *Context) slideOffset(t *Toast, width int) int {
+	off := int(math.Round((1 - t.anim) * float64(width+c.theme.SpaceM)))
+	if c.toastCorner == ToastBottomLeft || c.toastCorner == ToastTopLeft {
+		return -off
+	}
+	return off
+}
+
+// layoutToasts stacks the visible toasts from the configured corner. Toasts
+// leaving the stack shrink their slot so the others close the gap.
+func (c *Context) layoutToasts() {
+	w, h := c.toastSize()
+	gap := c.theme.SpaceS
+	margin := c.theme.SpaceM
+
+	left := c.toastCorner == ToastBottomLeft || c.toastCorner == ToastTopLeft
+	top := c.toastCorner == ToastTopLeft || c.toastCorner == ToastTopRight
+
+	x := c.screen.Max.X - margin - w
+	if left {
+		x = c.screen.Min.X + margin
+	}
+
+	offset := 0
+	for _, t := range c.toasts {
+		if !t.visible {
+			break
+		}
+
+		y := c.screen.Max.Y - margin - offset - h
+		if top {
+			y = c.screen.Min.Y + margin + offset
+		}
+
+		t.rect = image.Rect(x, y, x+w, y+h).Add(image.Pt(c.slideOffset(t, w), 0))
+		offset += int(math.Round(float64(h+gap) * t.anim))
+	}
+}
+
+// updateToasts animates the toasts and handles their pointer input. It
+// reports whether the pointer belongs to the toast layer this frame.
+func (c *Context) updateToasts() bool {
+	var dt time.Duration
+	if !c.toastTick.IsZero() {
+		dt = c.now.Sub(c.toastTick)
+	}
+	c.toastTick = c.now
+	c.toastPtr = c.ptr.Position
+
+	// Promote queued toasts, dropping those dismissed while waiting.
+	shown := 0
+	kept := c.toasts[:0]
+	for _, t := range c.toasts {
+		if !t.visible && t.dismissed {
+			continue
+		}
+		if !t.visible && shown < maxToasts {
+			t.visible = true
+		}
+		if t.visible {
+			shown++
+		}
+		kept = append(kept, t)
+	}
+	c.toasts = kept
+
+	if len(c.toasts) == 0 || c.screen.Empty() {
+		c.toastDrag = nil
+		c.toastPress = false
+		return false
+	}
+
+	ptr := c.ptr
+	hover := c.toastHover()
+
+	step := 1.0
+	if d := c.theme.AnimDuration; d > 0 {
+		step = float64(dt) / float64(d)
+	}
+
+	kept = c.toasts[:0]
+	for _, t := range c.toasts {
+		if t.visible {
+			if t.dismissed {
+				t.anim = math.Max(t.anim-step, 0)
+				if t.anim == 0 {
+					continue
+				}
+			} else {
+				t.anim = math.Min(t.anim+step, 1)
+				if t != hover && t != c.toastDrag {
+					t.elapsed += dt
+				}
+				if t.duration > 0 && t.elapsed >= t.duration {
+					t.dismissed = true
+				}
+			}
+		}
+		kept = append(kept, t)
+	}
+	c.toasts = kept
+	c.layoutToasts()
+
+	// Presses on a toast: action button, or the start of a swipe.
+	if ptr.IsJustDown && hover != nil && c.toastDrag == nil {
+		if ptr.Position.In(c.toastAction(hover, hover.drawRect())) {
+			hover.action()
+			hover.dismissed = true
+			c.toastPress = true
+			return true
+		}
+
+		c.toastDrag = hover
+		hover.pressX = ptr.Position.X
+	}
+
+	// The pointer is only claimed for presses that began on a toast. The
+	// release of a press that began elsewhere still reaches the widgets, so
+	// they get their PointerUp and click even when it ends over a toast.
+	t := c.toastDrag
+	if t == nil {
+		if c.toastPress {
+			c.toastPress = ptr.IsDown
+			return true
+		}
+		return hover != nil && !ptr.IsDown && !ptr.IsJustUp
+	}
+
+	t.swipe = ptr.Position.X - t.pressX
+	if ptr.IsJustUp || !ptr.IsDown {
+		c.toastDrag = nil
+
+		// A tap dismisses; a short drag snaps back.
+		dx := t.swipe
+		if dx < 0 {
+			dx = -dx
+		}
+		switch {
+		case dx < c.theme.SpaceS:
+			t.dismissed = true
+			t.swipe = 0
+		case dx > t.rect.Dx()/3:
+			t.dismissed = true
+		default:
+			t.swipe = 0
+		}
+	}
+	return true
+}
+
+func (c *Context) drawToasts(dst *ebiten.Image) {
+	th := c.theme
+	rtl := c.Direction() == DirectionRTL
+
+	for _, t := range c.toasts {
+		if !t.visible {
+			break
+		}
+
+		r := t.drawRect()
+		accent := c.levelColor(t.level)
+
+		drawRoundedRect(dst, r, th.Radius, th.SurfaceColor)
+		drawRoundedBorder(dst, r, th.Radius, th.BorderW, th.BorderColor)
+
+		// Accent bar on the leading edge.
+		bar := max(th.Radius/2, 4)
+		ar := image.Rect(r.Min.X, r.Min.Y, r.Min.X+bar, r.Max.Y)
+		if rtl {
+			ar = image.Rect(r.Max.X-bar, r.Min.Y, r.Max.X, r.Max.Y)
+		}
+		drawRoundedRect(dst, ar, bar/2, accent)
+
+		text := th.Text()
+		cy := r.Min.Y + r.Dy()/2
+
+		btn := c.toastAction(t, r)
+		if !btn.Empty() {
+			if t == c.toastHover() && c.toastPtr.In(btn) {
+				drawRoundedRect(dst, btn, th.Radius, th.SurfaceHoverColor)
+			}
+			text.SetColor(accent)
+			text.SetAlign(etxt.Center)
+			text.Draw(dst, t.actionLabel, btn.Min.X+btn.Dx()/2, cy)
+		}
+
+		// Message between the accent bar and the action button, clipped to
+		// that area.
+		tr := image.Rect(r.Min.X+bar+th.PadX, r.Min.Y, r.Max.X-th.PadX, r.Max.Y)
+		if rtl {
+			tr = image.Rect(r.Min.X+th.PadX, r.Min.Y, r.Max.X-bar-th.PadX, r.Max.Y)
+		}
+		if !btn.Empty() {
+			if rtl {
+				tr.Min.X = btn.Max.X + th.SpaceS
+			} else {
+				tr.Max.X = btn.Min.X - th.SpaceS
+			}
+		}
+		if tr.Empty() {
+			continue
+		}
+
+		clip := dst.SubImage(tr).(*ebiten.Image)
+		msg := common.VisualText(t.message, rtl)
+		text.SetColor(th.TextColor)
+		if rtl {
+			text.SetAlign(etxt.Right | etxt.VertCenter)
+			text.Draw(clip, msg, tr.Max.X, cy)
+		} else {
+			text.SetAlign(etxt.Left | etxt.VertCenter)
+			text.Draw(clip, msg, tr.Min.X, cy)
+		}
+	}
+}
+
+// toastHover returns the toast under the pointer, or nil.
+func (c *Context) toastHover() *Toast {
+	for _, t := range c.toasts {
+		if t.visible && !t.dismissed && c.toastPtr.In(t.drawRect()) {
+			return t
+		}
+	}
+	return nil
+}
@@ -36,6 +36,18 @@ type OverlayWidget interface {
 	DrawOverlay(ctx *Context, dst *ebiten.Image)
 }
 
+// ModalWidget is shown above the whole UI by Context.ShowDialog (e.g.
+// widget.Dialog). While it is open it is the only widget tree receiving
+// input and focus.
+type ModalWidget interface {
+	Widget
+	Children() []Widget
+	DrawOverlay(ctx *Context, dst *ebiten.Image)
+	// InitialFocus returns the widget focused when the dialog opens, or nil
+	// for its first focusable widget.
+	InitialFocus() Widget
+}
+
 type ValidableWidget interface {
 	IsValidable() bool
 	IsInvalid() (bool, string)
@@ -0,0 +1,251 @@
+package widget
+
+import (
+	"strings"
+
+	"github.com/erparts/go-uikit/common"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/tinne26/etxt"
+)
+
+// Bidirectional text is reordered per line for display by common.BidiLayout.
+// Editing always happens in logical order; bidiLine maps logical offsets to
+// visual positions and back.
+
+// bidiLine is one line of text laid out in visual (left to right) order.
+type bidiLine struct {
+	text     string
+	visual   string
+	clusters []common.BidiCluster
+	rtl      bool // paragraph direction
+}
+
+// layoutBidi lays out a single line (without '\n') for display.
+func layoutBidi(s string, rtl bool) *bidiLine {
+	l := &bidiLine{text: s, rtl: rtl}
+	l.visual, l.clusters = common.BidiLayout(s, rtl)
+	return l
+}
+
+// needsBidi reports whether s contains characters that can be RTL.
+func needsBidi(s string) bool { return common.NeedsBidi(s) }
+
+// caretX returns the visual x of a caret placed at logical offset off.
+func (l *bidiLine) caretX(off int, measure func(string) int) int {
+	for _, c := range l.clusters {
+		if c.Start == off {
+			if c.RTL {
+				return measure(l.visual[:c.VEnd])
+			}
+			return measure(l.visual[:c.VStart])
+		}
+	}
+
+	// End of text: trailing edge of the last logical cluster.
+	for _, c := range l.clusters {
+		if c.End == off {
+			if c.RTL {
+				return measure(l.visual[:c.VStart])
+			}
+			return measure(l.visual[:c.VEnd])
+		}
+	}
+
+	return 0
+}
+
+// offsetAt returns the logical cluster boundary closest to the visual x.
+func (l *bidiLine) offsetAt(x int, measure func(string) int) int {
+	if len(l.clusters) == 0 {
+		return 0
+	}
+
+	if x <= 0 {
+		c := l.clusters[0]
+		if c.RTL {
+			return c.End
+		}
+		return c.Start
+	}
+
+	for _, c := range l.clusters {
+		left, right := measure(l.visual[:c.VStart]), measure(l.visual[:c.VEnd])
+		if x >= right {
+			continue
+		}
+
+		leftHalf := x-left < right-x
+		if leftHalf != c.RTL {
+			return c.Start
+		}
+		return c.End
+	}
+
+	c := l.clusters[len(l.clusters)-1]
+	if c.RTL {
+		return c.Start
+	}
+	return c.End
+}
+
+// selectionSpans returns the visual x ranges covering logical range [a, b).
+func (l *bidiLine) selectionSpans(a, b int, measure func(string) int) [][2]int {
+	var spans [][2]int
+	for _, c := range l.clusters {
+		if c.Start < a || c.End > b {
+			continue
+		}
+
+		x0, x1 := measure(l.visual[:c.VStart]), measure(l.visual[:c.VEnd])
+		if n := len(spans); n > 0 && spans[n-1][1] == x0 {
+			spans[n-1][1] = x1
+			continue
+		}
+		spans = append(spans, [2]int{x0, x1})
+	}
+
+	return spans
+}
+
+// pieces calls fn for every run of consecutive visual clusters sharing a
+// style, from left to right.
+func (l *bidiLine) pieces(styleAt func(start int) TextStyle, fn func(vstart, vend int, st TextStyle)) {
+	if len(l.clusters) == 0 {
+		return
+	}
+
+	from := 0
+	st := styleAt(l.clusters[0].Start)
+	for _, c := range l.clusters[1:] {
+		next := styleAt(c.Start)
+		if next != st {
+			fn(from, c.VStart, st)
+			from, st = c.VStart, next
+		}
+	}
+
+	fn(from, len(l.visual), st)
+}
+
+// styledMeasure returns a measure function for prefixes of l.visual that
+// accounts for per-span fonts.
+func (l *bidiLine) styledMeasure(t *etxt.Renderer, styleAt func(start int) TextStyle) func(string) int {
+	base := t.GetFont()
+	return func(s string) int {
+		w := 0
+		l.pieces(styleAt, func(vstart, vend int, st TextStyle) {
+			if vstart >= len(s) {
+				return
+			}
+			if st.Font != nil {
+				t.SetFont(st.Font)
+			}
+			w += t.Measure(l.visual[vstart:min(vend, len(s))]).IntWidth()
+			t.SetFont(base)
+		})
+		return w
+	}
+}
+
+// drawPieces draws the line at (x, y) in visual order with the renderer
+// alignment. styleAt returns the resolved style of the cluster starting at the
+// given logical offset; backgrounds are drawn first so they never cover text.
+func (l *bidiLine) drawPieces(t *etxt.Renderer, dst *ebiten.Image, x, y int, styleAt func(start int) TextStyle) {
+	base := t.GetFont()
+	lineH := t.Measure(" ").IntHeight()
+	top := y
+	if t.GetAlign().Vert() == etxt.VertCenter {
+		top = y - lineH/2
+	}
+
+	type piece struct {
+		text string
+		x, w int
+		st   TextStyle
+	}
+
+	var ps []piece
+	px := x
+	l.pieces(styleAt, func(vstart, vend int, st TextStyle) {
+		if st.Font != nil {
+			t.SetFont(st.Font)
+		}
+		p := piece{text: l.visual[vstart:vend], x: px, w: t.Measure(l.visual[vstart:vend]).IntWidth(), st: st}
+		t.SetFont(base)
+
+		if st.Background.A != 0 {
+			vector.DrawFilledRect(dst, float32(p.x), float32(top), float32(p.w), float32(lineH), st.Background, false)
+		}
+		ps = append(ps, p)
+		px += p.w
+	})
+
+	underlineW := max(lineH/16, 1)
+	for _, p := range ps {
+		if p.st.Font != nil {
+			t.SetFont(p.st.Font)
+		}
+		drawTextPiece(t, dst, p.text, p.x, y, p.st.Color)
+		t.SetFont(base)
+
+		if p.st.Underline {
+			vector.DrawFilledRect(dst, float32(p.x), float32(top+lineH-underlineW), float32(p.w), float32(underlineW), p.st.Color, false)
+		}
+	}
+}
+
+// visualText reorders every line of s for display.
+func visualText(s string, rtl bool) string {
+	return common.VisualText(s, rtl)
+}
+
+// wrapText breaks s into lines no wider than maxW, on spaces when possible.
+// Existing '\n' breaks are kept.
+func wrapText(s string, maxW int, measure func(string) int) []string {
+	var out []string
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.SplitAfter(para, " ") {
+			if line != "" && measure(strings.TrimRight(line+word, " ")) > maxW {
+				out = append(out, strings.TrimRight(line, " "))
+				line = ""
+			}
+			line += word
+		}
+		out = append(out, strings.TrimRight(line, " "))
+	}
+
+	return out
+}
+
+// visualWrapped wraps s in logical order and reorders each resulting line,
+// ready to be drawn as a block with etxt.
+func visualWrapped(t *etxt.Renderer, s string, maxW int, rtl bool) string {
+	lines := wrapText(s, maxW, func(s string) int { return t.Measure(s).IntWidth() })
+	for i, line := range lines {
+		lines[i] = layoutBidi(line, rtl).visual
+	}
+	return strings.Join(lines, "\n")
+}
+
+// drawWrapped draws s wrapped to maxW with the renderer alignment. Text drawn
+// in an RTL widget, or containing RTL characters, is wrapped in logical order
+// and reordered per line; anything else goes straight to etxt.
+func drawWrapped(t *etxt.Renderer, dst *ebiten.Image, s string, x, y, maxW int, rtl bool) {
+	if !rtl && !needsBidi(s) {
+		t.DrawWithWrap(dst, s, x, y, maxW)
+		return
+	}
+
+	t.Draw(dst, visualWrapped(t, s, maxW, rtl), x, y)
+}
+
+// wrappedHeight returns the height of s as drawn by drawWrapped.
+func wrappedHeight(t *etxt.Renderer, s string, maxW int, rtl bool) int {
+	if !rtl && !needsBidi(s) {
+		return t.MeasureWithWrap(s, maxW).IntHeight()
+	}
+
+	return t.Measure(visualWrapped(t, s, maxW, rtl)).IntHeight()
+}
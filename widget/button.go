@@ -1,23 +1,56 @@
 package widget
 
 import (
+	"image/color"
+
 	"github.com/erparts/go-uikit"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 	"github.com/tinne26/etxt"
 )
 
 var _ uikit.Widget = (*Button)(nil)
 
+// ButtonVariant selects the look of a Button.
+type ButtonVariant int
+
+const (
+	// ButtonSecondary is the default surface coloured button.
+	ButtonSecondary ButtonVariant = iota
+	// ButtonPrimary is filled with the focus colour, for the main action.
+	ButtonPrimary
+	// ButtonDanger is filled with the error colour, for destructive actions.
+	ButtonDanger
+	// ButtonGhost has no surface until hovered.
+	ButtonGhost
+	// ButtonLink looks like a hyperlink.
+	ButtonLink
+)
+
 // Button is a clickable control with hover/pressed/disabled visuals.
 // - Click triggers on pointer release inside the widget.
 // - Enter/Space triggers click when focused.
+// - An optional icon is drawn before the label; with an empty label the
+// button shows the icon alone.
+// - Toggle buttons flip Checked on each click and stay pressed while on;
+// EventValueChange carries the new state.
+// - A button with a Form is the default button of that subtree: Enter
+// clicks it while the focus is on a widget of the form that does not use
+// Enter itself.
 type Button struct {
 	uikit.Base
 
 	label   string
+	icon    *uikit.Icon
 	OnClick func()
 
+	Variant ButtonVariant
+	Toggle  bool
+	Form    uikit.Widget
+
+	checked bool
+
 	// internal: tracks if the press started inside this widget
 	pressedInside bool
 }
@@ -33,14 +66,57 @@ func NewButton(theme *uikit.Theme, label string) *Button {
 	return b
 }
 
+// NewToggleButton creates a button in toggle mode.
+func NewToggleButton(theme *uikit.Theme, label string) *Button {
+	b := NewButton(theme, label)
+	b.Toggle = true
+	return b
+}
+
 func (w *Button) Focusable() bool { return true }
 
 func (w *Button) SetLabel(s string) {
 	w.label = s
 }
 
+// SetIcon sets the icon drawn before the label; nil removes it.
+func (w *Button) SetIcon(icon *uikit.Icon) {
+	w.icon = icon
+}
+
+func (w *Button) Icon() *uikit.Icon { return w.icon }
+
+// Checked reports whether a toggle button is on.
+func (w *Button) Checked() bool { return w.checked }
+
+// SetChecked switches a toggle button on or off.
+func (w *Button) SetChecked(v bool) {
+	if w.checked == v {
+		return
+	}
+	w.checked = v
+	w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange, Value: v})
+}
+
+// PreferredWidth returns the width fitting the icon and label with PadX on
+// both sides. Icon-only buttons are at least square.
+func (w *Button) PreferredWidth() int {
+	theme := w.Theme()
+	pw := iconTextWidth(theme, w.icon, w.label) + theme.PadX*2
+	if w.label == "" {
+		pw = max(pw, theme.ControlH)
+	}
+	return pw
+}
+
+func (w *Button) handlesEnter() bool { return true }
+
 // fireClick dispatches a click event and calls OnClick handler.
 func (w *Button) fireClick() {
+	if w.Toggle {
+		w.SetChecked(!w.checked)
+	}
+
 	w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventClick})
 	if w.OnClick != nil {
 		w.OnClick()
@@ -58,6 +134,11 @@ func (w *Button) Update(ctx *uikit.Context) {
 		return
 	}
 
+	if w.Form != nil && w.IsVisible() && formEnterPressed(ctx, w.Form) {
+		w.fireClick()
+		return
+	}
+
 	ptr := ctx.Pointer()
 	inside := ptr.Position.In(w.Measure(false))
 
@@ -75,32 +156,137 @@ func (w *Button) Update(ctx *uikit.Context) {
 	}
 }
 
+// usesEnter reports whether Enter has a meaning of its own on w (buttons,
+// text areas, lists with an activate handler).
+func usesEnter(w uikit.Widget) bool {
+	h, ok := any(w).(interface{ handlesEnter() bool })
+	return ok && h.handlesEnter()
+}
+
+// formEnterPressed reports whether Enter was pressed for the default button
+// of form: the focus is inside it, on a widget that does not use Enter, and
+// no overlay of the form is open. The focus is taken from the start of the
+// frame, since a TextInput drops it on Enter before later widgets update.
+func formEnterPressed(ctx *uikit.Context, form uikit.Widget) bool {
+	if !inpututil.IsKeyJustPressed(ebiten.KeyEnter) && !inpututil.IsKeyJustPressed(ebiten.KeyKPEnter) {
+		return false
+	}
+
+	focused := ctx.FrameFocused()
+	if usesEnter(focused) || !containsWidget(form, focused) {
+		return false
+	}
+	return !overlayOpen(form)
+}
+
+// colors returns the background (zero for none) and text colours for the
+// current state.
+func (w *Button) colors(theme *uikit.Theme) (bg, fg color.RGBA) {
+	on := w.Toggle && w.checked
+	hover := w.IsHovered()
+	pressed := w.IsPressed()
+
+	if !w.IsEnabled() {
+		if w.Variant == ButtonGhost || w.Variant == ButtonLink {
+			return color.RGBA{}, theme.DisabledColor
+		}
+		return theme.SurfacePressedColor, theme.DisabledColor
+	}
+
+	switch w.Variant {
+	case ButtonPrimary, ButtonDanger:
+		base := theme.FocusColor
+		if w.Variant == ButtonDanger {
+			base = theme.ErrorColor
+		}
+		switch {
+		case pressed || on:
+			bg = lerpColor(base, color.RGBA{0, 0, 0, 255}, 0.2)
+		case hover:
+			bg = lerpColor(base, color.RGBA{255, 255, 255, 255}, 0.15)
+		default:
+			bg = base
+		}
+		return bg, theme.BackgroundColor
+
+	case ButtonGhost:
+		switch {
+		case pressed:
+			bg = theme.SurfacePressedColor
+		case on:
+			bg = lerpColor(theme.SurfaceColor, theme.FocusColor, 0.35)
+		case hover:
+			bg = theme.SurfaceHoverColor
+		}
+		return bg, theme.TextColor
+
+	case ButtonLink:
+		fg = theme.FocusColor
+		if pressed || on {
+			fg = lerpColor(fg, theme.TextColor, 0.4)
+		}
+		return color.RGBA{}, fg
+	}
+
+	switch {
+	case pressed:
+		bg = theme.FocusColor
+	case on:
+		bg = lerpColor(theme.SurfaceColor, theme.FocusColor, 0.35)
+	case hover:
+		bg = theme.BorderColor
+	default:
+		bg = theme.SurfaceColor
+	}
+	return bg, theme.TextColor
+}
+
 func (w *Button) Draw(ctx *uikit.Context, dst *ebiten.Image) {
 	r := w.Measure(false)
-	w.Base.Draw(ctx, dst)
+	if r.Dy() == 0 {
+		w.SetFrame(r.Min.X, r.Min.Y, r.Dx())
+		r = w.Measure(false)
+	}
 
 	theme := ctx.Theme()
-	if w.IsEnabled() {
-		if w.IsPressed() {
-			w.DrawRoundedRect(dst, r, theme.Radius, theme.FocusColor)
-		} else if w.IsHovered() {
-			w.DrawRoundedRect(dst, r, theme.Radius, theme.BorderColor)
-		}
+	bg, col := w.colors(theme)
+	if bg.A != 0 {
+		w.DrawRoundedRect(dst, r, theme.Radius, bg)
+	}
+	if w.Variant == ButtonSecondary {
+		w.Base.DrawBoder(ctx, dst, r)
 	}
 
-	col := theme.TextColor
-	if !w.IsEnabled() {
-		col = theme.DisabledColor
+	// The default button of a form is outlined while another widget has
+	// the focus.
+	if w.Form != nil && !w.IsFocused() && w.IsEnabled() && w.Variant != ButtonLink {
+		w.DrawRoundedBorder(dst, r, theme.Radius, theme.BorderW, theme.FocusColor)
 	}
+	w.Base.DrawFocus(ctx, dst, r)
+	w.Base.DrawInvalid(ctx, dst, r)
 
-	t := theme.Text()
-	t.SetColor(col)
-	t.SetAlign(etxt.Center)
+	rtl := w.IsRTL(ctx)
+	cy := r.Min.Y + r.Dy()/2
 
-	offY := 0
-	if w.IsEnabled() && w.IsPressed() {
-		offY = 0
+	// Icon and label are centred as a group, or start at PadX when the
+	// button is too narrow for both.
+	cw := iconTextWidth(theme, w.icon, w.label)
+	if w.icon == nil {
+		t := theme.Text()
+		t.SetColor(col)
+		t.SetAlign(etxt.Center)
+		t.Draw(dst, visualText(w.label, rtl), r.Min.X+r.Dx()/2, cy)
+	} else {
+		x := r.Min.X + max((r.Dx()-cw)/2, theme.PadX)
+		if rtl {
+			x = r.Max.X - max((r.Dx()-cw)/2, theme.PadX)
+		}
+		drawIconText(theme, dst, w.icon, w.label, x, cy, col, rtl)
 	}
 
-	t.Draw(dst, w.label, r.Min.X+r.Dx()/2, r.Min.Y+r.Dy()/2+offY)
+	if w.Variant == ButtonLink && w.IsHovered() && w.IsEnabled() {
+		x := float32(r.Min.X + (r.Dx()-cw)/2)
+		y := float32(cy + theme.FontPx/2)
+		vector.StrokeLine(dst, x, y, x+float32(cw), y, 1, col, false)
+	}
 }
@@ -0,0 +1,221 @@
+package widget
+
+import (
+	"image"
+
+	"github.com/erparts/go-uikit"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+var _ uikit.Widget = (*ButtonGroup[int])(nil)
+
+// ButtonGroup is a segmented control: a row of buttons sharing their
+// borders, exactly one of which is selected, like a RadioGroup.
+// - Segments split the width evenly and may carry an icon (SetIcon).
+// - The group is a single Tab stop; arrows move the selection within it.
+// - EventValueChange carries the selected value in Event.Value.
+type ButtonGroup[T comparable] struct {
+	uikit.Base
+
+	options []RadioOption[T]
+	icons   []*uikit.Icon
+	index   int // selected segment, -1 if none
+	rtl     bool
+}
+
+func NewButtonGroup[T comparable](theme *uikit.Theme, options []RadioOption[T]) *ButtonGroup[T] {
+	cfg := uikit.NewWidgetBaseConfig(theme)
+	cfg.DrawSurface = false // drawn per segment
+
+	w := &ButtonGroup[T]{
+		Base:    uikit.NewBase(cfg),
+		options: options,
+		icons:   make([]*uikit.Icon, len(options)),
+		index:   -1,
+	}
+	w.Base.On(uikit.EventPointerDown, w.onPointerDown, false)
+
+	return w
+}
+
+func (w *ButtonGroup[T]) Focusable() bool { return len(w.options) > 0 }
+
+func (w *ButtonGroup[T]) SetOptions(options []RadioOption[T]) {
+	w.options = options
+	w.icons = make([]*uikit.Icon, len(options))
+	if w.index >= len(options) {
+		w.index = -1
+	}
+}
+
+func (w *ButtonGroup[T]) Options() []RadioOption[T] { return w.options }
+
+// SetIcon sets the icon of segment i; nil removes it.
+func (w *ButtonGroup[T]) SetIcon(i int, icon *uikit.Icon) {
+	if i >= 0 && i < len(w.icons) {
+		w.icons[i] = icon
+	}
+}
+
+// Index returns the selected segment, or -1.
+func (w *ButtonGroup[T]) Index() int { return w.index }
+
+// SetIndex selects a segment; -1 clears the selection.
+func (w *ButtonGroup[T]) SetIndex(i int) {
+	if i < -1 || i >= len(w.options) || i == w.index {
+		return
+	}
+
+	w.index = i
+	var v any
+	if i >= 0 {
+		v = w.options[i].Value
+	}
+	w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange, Value: v})
+}
+
+// Value returns the selected value.
+func (w *ButtonGroup[T]) Value() (T, bool) {
+	if w.index < 0 {
+		var zero T
+		return zero, false
+	}
+	return w.options[w.index].Value, true
+}
+
+// SetValue selects the first segment holding v. It reports whether one was
+// found.
+func (w *ButtonGroup[T]) SetValue(v T) bool {
+	for i, o := range w.options {
+		if o.Value == v {
+			w.SetIndex(i)
+			return true
+		}
+	}
+	return false
+}
+
+// segment returns the rect of segment i, mirrored in RTL.
+func (w *ButtonGroup[T]) segment(i int) image.Rectangle {
+	r := w.Measure(false)
+	n := len(w.options)
+	if w.rtl {
+		i = n - 1 - i
+	}
+
+	x0 := r.Min.X + r.Dx()*i/n
+	x1 := r.Min.X + r.Dx()*(i+1)/n
+	return image.Rect(x0, r.Min.Y, x1, r.Max.Y)
+}
+
+func (w *ButtonGroup[T]) segmentAt(p image.Point) int {
+	for i := range w.options {
+		if p.In(w.segment(i)) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (w *ButtonGroup[T]) onPointerDown(e uikit.Event) bool {
+	if !w.IsEnabled() || e.Pointer == nil {
+		return false
+	}
+
+	if i := w.segmentAt(e.Pointer.Position); i >= 0 {
+		w.SetIndex(i)
+	}
+	return false
+}
+
+func (w *ButtonGroup[T]) Update(ctx *uikit.Context) {
+	r := w.Measure(false)
+	w.SetFrame(r.Min.X, r.Min.Y, r.Dx())
+	w.rtl = w.IsRTL(ctx)
+
+	n := len(w.options)
+	if !w.IsEnabled() || !w.IsFocused() || n == 0 {
+		return
+	}
+
+	prev, next := ebiten.KeyLeft, ebiten.KeyRight
+	if w.rtl {
+		prev, next = next, prev
+	}
+
+	cur := max(w.index, 0)
+	switch {
+	case keyRepeat(next) || keyRepeat(ebiten.KeyDown):
+		w.SetIndex((cur + 1) % n)
+	case keyRepeat(prev) || keyRepeat(ebiten.KeyUp):
+		w.SetIndex((cur - 1 + n) % n)
+	case inpututil.IsKeyJustPressed(ebiten.KeyHome):
+		w.SetIndex(0)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnd):
+		w.SetIndex(n - 1)
+	case inpututil.IsKeyJustPressed(ebiten.KeySpace) && w.index < 0:
+		w.SetIndex(0)
+	}
+}
+
+func (w *ButtonGroup[T]) Draw(ctx *uikit.Context, dst *ebiten.Image) {
+	theme := ctx.Theme()
+	r := w.Measure(false)
+	rtl := w.IsRTL(ctx)
+	w.rtl = rtl
+
+	bg := theme.SurfaceColor
+	if !w.IsEnabled() {
+		bg = theme.SurfacePressedColor
+	}
+	w.DrawRoundedRect(dst, r, theme.Radius, bg)
+
+	// Segment fills are rounded, then squared off again on the edges shared
+	// with a neighbour so only the outer corners stay round.
+	ptr := ctx.Pointer()
+	for i, o := range w.options {
+		seg := w.segment(i)
+
+		fill := bg
+		col := theme.TextColor
+		switch {
+		case !w.IsEnabled():
+			col = theme.DisabledColor
+		case i == w.index:
+			fill = lerpColor(theme.SurfaceColor, theme.FocusColor, 0.35)
+		case w.IsHovered() && ptr.Position.In(seg):
+			fill = theme.SurfaceHoverColor
+		}
+		if !w.IsEnabled() && i == w.index {
+			fill = theme.SurfaceHoverColor
+		}
+
+		if fill != bg {
+			w.DrawRoundedRect(dst, seg, theme.Radius, fill)
+			if seg.Min.X > r.Min.X {
+				w.DrawRoundedRect(dst, image.Rect(seg.Min.X, seg.Min.Y, seg.Min.X+theme.Radius, seg.Max.Y), 0, fill)
+			}
+			if seg.Max.X < r.Max.X {
+				w.DrawRoundedRect(dst, image.Rect(seg.Max.X-theme.Radius, seg.Min.Y, seg.Max.X, seg.Max.Y), 0, fill)
+			}
+		}
+
+		// Shared border on the leading edge of every segment but the first.
+		if seg.Min.X > r.Min.X {
+			w.DrawRoundedRect(dst, image.Rect(seg.Min.X, r.Min.Y, seg.Min.X+theme.BorderW, r.Max.Y), 0, theme.BorderColor)
+		}
+
+		icon := w.icons[i]
+		cw := iconTextWidth(theme, icon, o.Label)
+		x := seg.Min.X + max((seg.Dx()-cw)/2, 0)
+		if rtl {
+			x = seg.Max.X - max((seg.Dx()-cw)/2, 0)
+		}
+		drawIconText(theme, dst, icon, o.Label, x, seg.Min.Y+seg.Dy()/2, col, rtl)
+	}
+
+	w.Base.DrawBoder(ctx, dst, r)
+	w.Base.DrawFocus(ctx, dst, r)
+	w.Base.DrawInvalid(ctx, dst, r)
+}
@@ -2,6 +2,7 @@ package widget
 
 import (
 	"image"
+	"image/color"
 	"math"
 
 	"github.com/erparts/go-uikit"
@@ -14,11 +15,22 @@ import (
 
 var _ uikit.Widget = (*Checkbox)(nil)
 
+// CheckState is the state of a Checkbox.
+type CheckState int
+
+const (
+	CheckUnchecked CheckState = iota
+	CheckChecked
+	// CheckIndeterminate is drawn as a dash, e.g. for a parent whose children
+	// are partly checked (see CheckboxGroup). Toggling it checks the box.
+	CheckIndeterminate
+)
+
 type Checkbox struct {
 	uikit.Base
 
-	label   string
-	checked bool
+	label string
+	state CheckState
 
 	lastHeight int
 	refWidth   int
@@ -46,14 +58,34 @@ func (w *Checkbox) heightCalculator() int {
 func (w *Checkbox) Focusable() bool { return true }
 
 func (w *Checkbox) SetChecked(v bool) {
-	if w.checked == v {
+	if v {
+		w.SetState(CheckChecked)
+	} else {
+		w.SetState(CheckUnchecked)
+	}
+}
+
+// Checked reports whether the box is checked (not indeterminate).
+func (w *Checkbox) Checked() bool { return w.state == CheckChecked }
+
+// SetState sets the state. EventValueChange carries the new CheckState.
+func (w *Checkbox) SetState(s CheckState) {
+	if w.state == s {
 		return
 	}
-	w.checked = v
-	w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange})
+	w.state = s
+	w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange, Value: s})
 }
 
-func (w *Checkbox) Checked() bool { return w.checked }
+func (w *Checkbox) State() CheckState { return w.state }
+
+func (w *Checkbox) SetIndeterminate() { w.SetState(CheckIndeterminate) }
+
+func (w *Checkbox) IsIndeterminate() bool { return w.state == CheckIndeterminate }
+
+func (w *Checkbox) toggle() {
+	w.SetChecked(w.state != CheckChecked)
+}
 
 func (w *Checkbox) onClick(e uikit.Event) bool {
 	if !w.IsEnabled() {
@@ -61,7 +93,7 @@ func (w *Checkbox) onClick(e uikit.Event) bool {
 	}
 
 	if e.Type == uikit.EventClick {
-		w.SetChecked(!w.Checked())
+		w.toggle()
 	}
 
 	return false
@@ -75,11 +107,7 @@ func (w *Checkbox) Update(ctx *uikit.Context) {
 
 	if w.refWidth != r.Dx() {
 		w.refWidth = r.Dx()
-		boxSize, boxHorzIntsp, padX, padY := w.boxMetrics(ctx)
-		maxLineLen := max(w.refWidth-(boxSize+boxHorzIntsp+padX*2), 0)
-		theme := ctx.Theme()
-		w.lastHeight = theme.Text().MeasureWithWrap(w.label, maxLineLen).IntHeight()
-		w.lastHeight = max(w.lastHeight+padY*2, theme.ControlH)
+		w.lastHeight = checkRowHeight(ctx.Theme(), w.label, w.refWidth, w.IsRTL(ctx))
 	}
 
 	if !w.IsEnabled() {
@@ -88,7 +116,7 @@ func (w *Checkbox) Update(ctx *uikit.Context) {
 
 	// Keyboard toggle
 	if w.IsFocused() && inpututil.IsKeyJustPressed(ebiten.KeySpace) {
-		w.SetChecked(!w.Checked())
+		w.toggle()
 	}
 }
 
@@ -98,10 +126,9 @@ func (w *Checkbox) Draw(ctx *uikit.Context, dst *ebiten.Image) {
 	theme := ctx.Theme()
 	r := w.Measure(false)
 
-	boxSize, boxHorzIntsp, padX, padY := w.boxMetrics(ctx)
-	content := common.Inset(r, padX, padY)
-	boxY := r.Min.Y + (r.Dy()-boxSize)/2
-	box := image.Rect(content.Min.X, boxY, content.Min.X+boxSize, boxY+boxSize)
+	rtl := w.IsRTL(ctx)
+	box := checkRowBox(theme, r, rtl)
+	boxSize := box.Dx()
 
 	// Colors
 	bg := theme.BackgroundColor
@@ -131,39 +158,92 @@ func (w *Checkbox) Draw(ctx *uikit.Context, dst *ebiten.Image) {
 	w.Base.DrawRoundedRect(dst, box, radius, bg)
 	w.Base.DrawRoundedBorder(dst, box, radius, theme.BorderW, border)
 
-	if w.checked {
-		x1 := float32(box.Min.X) + float32(boxSize)*0.22
-		y1 := float32(box.Min.Y) + float32(boxSize)*0.55
-		x2 := float32(box.Min.X) + float32(boxSize)*0.42
-		y2 := float32(box.Min.Y) + float32(boxSize)*0.73
-		x3 := float32(box.Min.X) + float32(boxSize)*0.78
-		y3 := float32(box.Min.Y) + float32(boxSize)*0.30
-
-		strokeW := float32(theme.BorderW)
-		if strokeW < 2 {
-			strokeW = 2
-		}
-		// Slightly thicker for better readability at small sizes
-		if strokeW < float32(boxSize)/8 {
-			strokeW = float32(boxSize) / 8
-		}
+	strokeW := float32(theme.BorderW)
+	if strokeW < 2 {
+		strokeW = 2
+	}
+	// Slightly thicker for better readability at small sizes
+	if strokeW < float32(boxSize)/8 {
+		strokeW = float32(boxSize) / 8
+	}
 
-		vector.StrokeLine(dst, x1, y1, x2, y2, strokeW, checkCol, true)
-		vector.StrokeLine(dst, x2, y2, x3, y3, strokeW, checkCol, true)
+	switch w.state {
+	case CheckIndeterminate:
+		y := float32(box.Min.Y) + float32(boxSize)*0.5
+		x1 := float32(box.Min.X) + float32(boxSize)*0.25
+		x2 := float32(box.Min.X) + float32(boxSize)*0.75
+		vector.StrokeLine(dst, x1, y, x2, y, strokeW, checkCol, true)
+	case CheckChecked:
+		drawCheckMark(theme, dst, box, strokeW, checkCol)
 	}
 
-	t := theme.Text()
-	t.SetColor(textCol)
-	t.SetAlign(etxt.Left | etxt.VertCenter)
-	maxLineLen := max(r.Dx()-(boxSize+boxHorzIntsp+padX*2), 0)
-	t.DrawWithWrap(dst, w.label, box.Max.X+boxHorzIntsp, r.Min.Y+r.Dy()/2, maxLineLen)
+	drawCheckLabel(theme, dst, w.label, r, box, textCol, rtl)
 }
 
-func (w *Checkbox) boxMetrics(ctx *uikit.Context) (size, horzInterspace, padX, padY int) {
-	theme := ctx.Theme()
+// drawCheckMark draws the theme check icon, or a tick, inside box (also
+// used by checkable menu items).
+func drawCheckMark(theme *uikit.Theme, dst *ebiten.Image, box image.Rectangle, strokeW float32, col color.RGBA) {
+	if icon := theme.Icon(uikit.IconCheck); icon != nil {
+		icon.Draw(dst, box, col)
+		return
+	}
+
+	size := float32(box.Dx())
+	x1 := float32(box.Min.X) + size*0.22
+	y1 := float32(box.Min.Y) + size*0.55
+	x2 := float32(box.Min.X) + size*0.42
+	y2 := float32(box.Min.Y) + size*0.73
+	x3 := float32(box.Min.X) + size*0.78
+	y3 := float32(box.Min.Y) + size*0.30
+
+	vector.StrokeLine(dst, x1, y1, x2, y2, strokeW, col, true)
+	vector.StrokeLine(dst, x2, y2, x3, y3, strokeW, col, true)
+}
+
+// The helpers below lay out the rows of the check-style widgets (Checkbox,
+// RadioGroup): a box on the leading side and a wrapped label next to it.
+
+func checkMetrics(theme *uikit.Theme) (size, horzInterspace, padX, padY int) {
 	boxSize := theme.CheckSize
 	if boxSize < 12 {
 		boxSize = 12
 	}
 	return boxSize, theme.SpaceS, theme.PadX, theme.PadY
 }
+
+// checkRowHeight returns the height of a row of the given width.
+func checkRowHeight(theme *uikit.Theme, label string, width int, rtl bool) int {
+	boxSize, boxHorzIntsp, padX, padY := checkMetrics(theme)
+	maxLineLen := max(width-(boxSize+boxHorzIntsp+padX*2), 0)
+	h := wrappedHeight(theme.Text(), label, maxLineLen, rtl)
+	return max(h+padY*2, theme.ControlH)
+}
+
+// checkRowBox returns the box of row r, mirrored in RTL.
+func checkRowBox(theme *uikit.Theme, r image.Rectangle, rtl bool) image.Rectangle {
+	boxSize, _, padX, padY := checkMetrics(theme)
+	content := common.Inset(r, padX, padY)
+	boxY := r.Min.Y + (r.Dy()-boxSize)/2
+	if rtl {
+		// Mirrored: box on the right, label flowing to its left.
+		return image.Rect(content.Max.X-boxSize, boxY, content.Max.X, boxY+boxSize)
+	}
+	return image.Rect(content.Min.X, boxY, content.Min.X+boxSize, boxY+boxSize)
+}
+
+// drawCheckLabel draws the wrapped label of row r next to its box.
+func drawCheckLabel(theme *uikit.Theme, dst *ebiten.Image, label string, r, box image.Rectangle, col color.RGBA, rtl bool) {
+	boxSize, boxHorzIntsp, padX, _ := checkMetrics(theme)
+
+	t := theme.Text()
+	t.SetColor(col)
+	maxLineLen := max(r.Dx()-(boxSize+boxHorzIntsp+padX*2), 0)
+	if rtl {
+		t.SetAlign(etxt.Right | etxt.VertCenter)
+		drawWrapped(t, dst, label, box.Min.X-boxHorzIntsp, r.Min.Y+r.Dy()/2, maxLineLen, rtl)
+		return
+	}
+
+	t.SetAlign(etxt.Left | etxt.VertCenter)
+	drawWrapped(t, dst, label, box.Max.X+boxHorzIntsp, r.Min.Y+r.Dy()/2, maxLineLen, rtl)
+}
@@ -0,0 +1,140 @@
+package widget
+
+import "github.com/erparts/go-uikit"
+
+// CheckboxGroup links a parent Checkbox to its children ("select all").
+// - The parent shows checked when all children are, unchecked when none are,
+// and indeterminate otherwise.
+// - Toggling the parent checks or unchecks every child.
+// - The group dispatches a single EventValueChange per change, whoever
+// triggered it, with the parent as Event.Widget and the parent state as
+// Event.Value.
+//
+// The group is not a widget: the parent and the children are added to
+// layouts as usual.
+type CheckboxGroup struct {
+	uikit.EventDispatcher
+
+	parent   *Checkbox
+	children []*Checkbox
+
+	syncing bool
+}
+
+func NewCheckboxGroup(parent *Checkbox, children ...*Checkbox) *CheckboxGroup {
+	g := &CheckboxGroup{
+		EventDispatcher: uikit.NewEventDispatcher(),
+		parent:          parent,
+	}
+
+	parent.On(uikit.EventValueChange, g.onParentChange, false)
+	g.Add(children...)
+
+	return g
+}
+
+// Add adds children to the group and refreshes the parent state.
+func (g *CheckboxGroup) Add(children ...*Checkbox) {
+	for _, c := range children {
+		g.children = append(g.children, c)
+		c.On(uikit.EventValueChange, g.onChildChange, false)
+	}
+	g.sync()
+}
+
+func (g *CheckboxGroup) Parent() *Checkbox     { return g.parent }
+func (g *CheckboxGroup) Children() []*Checkbox { return g.children }
+
+// State returns the aggregated state of the children.
+func (g *CheckboxGroup) State() CheckState {
+	checked := 0
+	for _, c := range g.children {
+		if c.Checked() {
+			checked++
+		}
+	}
+
+	switch {
+	case checked == 0:
+		return CheckUnchecked
+	case checked == len(g.children):
+		return CheckChecked
+	default:
+		return CheckIndeterminate
+	}
+}
+
+// Checked returns the checked children.
+func (g *CheckboxGroup) Checked() []*Checkbox {
+	var out []*Checkbox
+	for _, c := range g.children {
+		if c.Checked() {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// SetAll checks or unchecks every child.
+func (g *CheckboxGroup) SetAll(v bool) {
+	g.parent.SetChecked(v)
+}
+
+// sync updates the parent from the children and reports whether it changed.
+func (g *CheckboxGroup) sync() bool {
+	st := g.State()
+	if g.parent.State() == st {
+		return false
+	}
+
+	g.syncing = true
+	g.parent.SetState(st)
+	g.syncing = false
+	return true
+}
+
+func (g *CheckboxGroup) dispatch() {
+	g.Dispatch(uikit.Event{Widget: g.parent, Type: uikit.EventValueChange, Value: g.parent.State()})
+}
+
+func (g *CheckboxGroup) onParentChange(e uikit.Event) bool {
+	if g.syncing {
+		return false
+	}
+
+	// Only a checked or unchecked parent drives the children. Indeterminate
+	// can't be picked by the user; when set from code, the parent goes back
+	// to the state the children dictate.
+	if g.parent.State() == CheckIndeterminate {
+		g.sync()
+		return false
+	}
+	v := g.parent.Checked()
+
+	g.syncing = true
+	changed := false
+	for _, c := range g.children {
+		if c.Checked() != v {
+			c.SetChecked(v)
+			changed = true
+		}
+	}
+	g.syncing = false
+
+	// With no children the parent keeps the state the children dictate.
+	g.sync()
+	if changed {
+		g.dispatch()
+	}
+	return false
+}
+
+func (g *CheckboxGroup) onChildChange(e uikit.Event) bool {
+	if g.syncing {
+		return false
+	}
+
+	g.sync()
+	g.dispatch()
+	return false
+}
@@ -0,0 +1,780 @@
+package widget
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/erparts/go-uikit"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+var _ uikit.Widget = (*ColorPicker)(nil)
+var _ uikit.ModalWidget = (*colorPopup)(nil)
+
+// colorArea is the part of a ColorPicker being dragged.
+type colorArea int
+
+const (
+	colorNone colorArea = iota
+	colorSV
+	colorHue
+	colorAlpha
+)
+
+// ColorPicker picks a color.RGBA.
+// - A saturation/value square and a hue strip, plus an alpha strip with
+// ShowAlpha; dragging any of them changes the colour.
+// - Hex (#RGB, #RRGGBB or #RRGGBBAA) and R, G, B (A) fields take typed
+// values. They are TextInputs of their own in the Tab order.
+// - Swatches are preset colours picked with a click.
+// - With Compact the widget is a single row showing the colour, which opens
+// the full picker in a popup below it (Enter, Space or a click).
+// - Arrows move the saturation/value marker (Shift for larger steps) and
+// PageUp/PageDown turn the hue.
+// - Color is alpha-premultiplied like every color.RGBA; the fields show the
+// straight components. EventValueChange carries the new color.RGBA.
+type ColorPicker struct {
+	uikit.Base
+
+	ShowAlpha bool
+	Compact   bool
+	Swatches  []color.RGBA
+
+	h, s, v float64 // hue in [0, 360], saturation and value in [0, 1]
+	a       float64
+	last    color.NRGBA // value of the last EventValueChange
+
+	hex    *TextInput
+	fields [4]*TextInput // R, G, B, A
+
+	drag  colorArea
+	width int // last laid out width, for the height
+	popup *colorPopup
+}
+
+// colorLayout holds the picker rectangles, in screen coordinates.
+type colorLayout struct {
+	sv, hue, alpha image.Rectangle
+	preview, hex   image.Rectangle
+	fields         [4]image.Rectangle
+	swatches       []image.Rectangle
+	height         int
+}
+
+func NewColorPicker(theme *uikit.Theme, c color.RGBA) *ColorPicker {
+	cfg := uikit.NewWidgetBaseConfig(theme)
+
+	w := &ColorPicker{
+		Base: uikit.NewBase(cfg),
+		hex:  NewTextInput(theme, "#RRGGBB"),
+	}
+	w.hex.SetMaxLength(9)
+	w.Base.HeightCalculator = w.heightCalculator
+
+	w.hex.On(uikit.EventValueChange, w.onHexChange, false)
+	w.hex.On(uikit.EventFocusLost, w.onFieldBlur, false)
+	for i, name := range []string{"R", "G", "B", "A"} {
+		in := NewTextInput(theme, name)
+		in.SetMaxLength(3)
+		in.On(uikit.EventValueChange, func(uikit.Event) bool { return w.onFieldChange(i) }, false)
+		in.On(uikit.EventFocusLost, w.onFieldBlur, false)
+		w.fields[i] = in
+	}
+
+	w.setNRGBA(color.NRGBAModel.Convert(c).(color.NRGBA), nil)
+	return w
+}
+
+func (w *ColorPicker) heightCalculator() int {
+	theme := w.Theme()
+	if w.Compact {
+		return theme.ControlH
+	}
+	return w.layout(theme, image.Rect(0, 0, w.width, 0), false).height
+}
+
+func (w *ColorPicker) Focusable() bool { return true }
+
+// handlesEnter: Enter opens the popup of a compact picker.
+func (w *ColorPicker) handlesEnter() bool { return w.Compact }
+
+// Children exposes the text fields, so they take part in focus and Tab.
+func (w *ColorPicker) Children() []uikit.Widget {
+	if w.Compact {
+		return nil
+	}
+
+	var out []uikit.Widget
+	for _, in := range w.inputs() {
+		out = append(out, in)
+	}
+	return out
+}
+
+func (w *ColorPicker) inputs() []*TextInput {
+	out := []*TextInput{w.hex, w.fields[0], w.fields[1], w.fields[2]}
+	if w.ShowAlpha {
+		out = append(out, w.fields[3])
+	}
+	return out
+}
+
+func (w *ColorPicker) SetFrame(x, y, width int) {
+	w.width = max(width, 0)
+	w.Base.SetFrame(x, y, width)
+}
+
+func (w *ColorPicker) SetEnabled(v bool) {
+	w.Base.SetEnabled(v)
+	for _, in := range w.fields {
+		in.SetEnabled(v)
+	}
+	w.hex.SetEnabled(v)
+}
+
+// Color returns the picked colour.
+func (w *ColorPicker) Color() color.RGBA {
+	return color.RGBAModel.Convert(w.nrgba()).(color.RGBA)
+}
+
+// SetColor sets the picked colour. The hue is kept for greys.
+func (w *ColorPicker) SetColor(c color.RGBA) {
+	if c == w.Color() && w.last == w.nrgba() {
+		return
+	}
+	w.setNRGBA(color.NRGBAModel.Convert(c).(color.NRGBA), nil)
+}
+
+func (w *ColorPicker) nrgba() color.NRGBA {
+	r, g, b := hsvToRGB(w.h, w.s, w.v)
+	return color.NRGBA{r, g, b, uint8(math.Round(w.a * 255))}
+}
+
+// setNRGBA sets the colour from straight components typed in src (nil if
+// not typed).
+func (w *ColorPicker) setNRGBA(c color.NRGBA, src *TextInput) {
+	h, s, v := rgbToHSV(c.R, c.G, c.B)
+	if s == 0 || v == 0 {
+		h = w.h
+	}
+	if v == 0 {
+		s = w.s
+	}
+
+	w.h, w.s, w.v, w.a = h, s, v, float64(c.A)/255
+	w.changed(src)
+}
+
+// changed refreshes the fields but src and dispatches EventValueChange when
+// the colour differs from the last one reported.
+func (w *ColorPicker) changed(src *TextInput) {
+	w.h = math.Mod(math.Max(w.h, 0), 360)
+	w.s = math.Min(math.Max(w.s, 0), 1)
+	w.v = math.Min(math.Max(w.v, 0), 1)
+	w.a = math.Min(math.Max(w.a, 0), 1)
+	w.syncFields(src)
+
+	if c := w.nrgba(); c != w.last {
+		w.last = c
+		w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange, Value: w.Color()})
+	}
+}
+
+func (w *ColorPicker) hexText() string {
+	c := w.nrgba()
+	if w.ShowAlpha {
+		return fmt.Sprintf("#%02X%02X%02X%02X", c.R, c.G, c.B, c.A)
+	}
+	return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+}
+
+func (w *ColorPicker) syncFields(src *TextInput) {
+	set := func(in *TextInput, s string) {
+		if in != src {
+			in.SetTextSilently(s)
+			in.ClearInvalid()
+		}
+	}
+
+	c := w.nrgba()
+	set(w.hex, w.hexText())
+	for i, v := range []uint8{c.R, c.G, c.B, c.A} {
+		set(w.fields[i], strconv.Itoa(int(v)))
+	}
+}
+
+// onHexChange applies the hex field once it holds a complete colour. Shorter
+// entries are pending rather than invalid, so the field row doesn't grow an
+// error line while the user types; onFieldBlur puts back the current value
+// if the field is left unfinished.
+func (w *ColorPicker) onHexChange(uikit.Event) bool {
+	c, hasAlpha, ok := parseHexColor(w.hex.Text())
+	if !ok {
+		if hexPending(w.hex.Text()) {
+			w.hex.ClearInvalid()
+		} else {
+			w.hex.SetInvalid("#RRGGBB")
+		}
+		return false
+	}
+	if !hasAlpha {
+		c.A = w.nrgba().A
+	}
+
+	w.hex.ClearInvalid()
+	w.setNRGBA(c, w.hex)
+	return false
+}
+
+func (w *ColorPicker) onFieldChange(i int) bool {
+	in := w.fields[i]
+	text := strings.TrimSpace(in.Text())
+	if text == "" {
+		in.ClearInvalid() // pending, like a partial hex entry
+		return false
+	}
+
+	n, err := strconv.Atoi(text)
+	if err != nil || n < 0 || n > 255 {
+		in.SetInvalid("0–255")
+		return false
+	}
+
+	c := w.nrgba()
+	switch i {
+	case 0:
+		c.R = uint8(n)
+	case 1:
+		c.G = uint8(n)
+	case 2:
+		c.B = uint8(n)
+	case 3:
+		c.A = uint8(n)
+	}
+
+	in.ClearInvalid()
+	w.setNRGBA(c, in)
+	return false
+}
+
+// onFieldBlur puts the current value back into a field left with an
+// unfinished entry.
+func (w *ColorPicker) onFieldBlur(uikit.Event) bool {
+	w.syncFields(nil)
+	return false
+}
+
+// hexPending reports whether s can still become a colour parseHexColor
+// accepts: an optional # and fewer than 8 hex digits.
+func hexPending(s string) bool {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) >= 8 {
+		return false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseHexColor parses #RGB, #RRGGBB or #RRGGBBAA; the # is optional.
+func parseHexColor(s string) (c color.NRGBA, hasAlpha, ok bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 && len(s) != 8 {
+		return c, false, false
+	}
+
+	n, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return c, false, false
+	}
+
+	if len(s) == 6 {
+		return color.NRGBA{uint8(n >> 16), uint8(n >> 8), uint8(n), 255}, false, true
+	}
+	return color.NRGBA{uint8(n >> 24), uint8(n >> 16), uint8(n >> 8), uint8(n)}, true, true
+}
+
+// hsvToRGB converts a hue in degrees and a saturation and value in [0, 1].
+func hsvToRGB(h, s, v float64) (r, g, b uint8) {
+	h = math.Mod(h, 360)
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := v - c
+
+	var r1, g1, b1 float64
+	switch {
+	case h < 60:
+		r1, g1, b1 = c, x, 0
+	case h < 120:
+		r1, g1, b1 = x, c, 0
+	case h < 180:
+		r1, g1, b1 = 0, c, x
+	case h < 240:
+		r1, g1, b1 = 0, x, c
+	case h < 300:
+		r1, g1, b1 = x, 0, c
+	default:
+		r1, g1, b1 = c, 0, x
+	}
+
+	f := func(v float64) uint8 { return uint8(math.Round((v + m) * 255)) }
+	return f(r1), f(g1), f(b1)
+}
+
+func rgbToHSV(r, g, b uint8) (h, s, v float64) {
+	rf, gf, bf := float64(r)/255, float64(g)/255, float64(b)/255
+	hi, lo := max(rf, gf, bf), min(rf, gf, bf)
+	d := hi - lo
+
+	v = hi
+	if hi > 0 {
+		s = d / hi
+	}
+
+	switch {
+	case d == 0:
+		h = 0
+	case hi == rf:
+		h = 60 * math.Mod((gf-bf)/d, 6)
+	case hi == gf:
+		h = 60 * ((bf-rf)/d + 2)
+	default:
+		h = 60 * ((rf-gf)/d + 4)
+	}
+	if h < 0 {
+		h += 360
+	}
+	return h, s, v
+}
+
+func hueColor(h float64) color.RGBA {
+	r, g, b := hsvToRGB(h, 1, 1)
+	return color.RGBA{r, g, b, 255}
+}
+
+// rowHeight is the height of the field row, which grows by an error line
+// while a field is invalid.
+func (w *ColorPicker) rowHeight(theme *uikit.Theme) int {
+	for _, in := range w.inputs() {
+		if ok, _ := in.IsInvalid(); ok {
+			return theme.ControlH + theme.ErrorGap + theme.ErrorText().Measure(" ").IntHeight()
+		}
+	}
+	return theme.ControlH
+}
+
+// layout places the parts of the full picker in r; only the top and the
+// width of r are used. In RTL the strips and fields are mirrored, the
+// square itself is not.
+func (w *ColorPicker) layout(theme *uikit.Theme, r image.Rectangle, rtl bool) colorLayout {
+	var l colorLayout
+	gap := theme.SpaceS
+	strip := theme.ControlH * 2 / 3
+	sq := theme.ControlH * 5
+	y := r.Min.Y
+
+	// Strips on the trailing side of the square.
+	x := r.Max.X
+	if w.ShowAlpha {
+		l.alpha = image.Rect(x-strip, y, x, y+sq)
+		x -= strip + gap
+	}
+	l.hue = image.Rect(x-strip, y, x, y+sq)
+	l.sv = image.Rect(r.Min.X, y, x-strip-gap, y+sq)
+	y += sq + gap
+
+	// Preview, hex field, then the component fields sharing the rest.
+	n := 3
+	if w.ShowAlpha {
+		n = 4
+	}
+	l.preview = image.Rect(r.Min.X, y, r.Min.X+theme.ControlH, y+theme.ControlH)
+	x = l.preview.Max.X + gap
+	rest := r.Max.X - x
+	l.hex = image.Rect(x, y, x+rest*2/5, y+theme.ControlH)
+	fw := (rest - l.hex.Dx() - n*gap) / n
+	x = l.hex.Max.X + gap
+	for i := range n {
+		l.fields[i] = image.Rect(x, y, x+fw, y+theme.ControlH)
+		x += fw + gap
+	}
+	y += w.rowHeight(theme)
+
+	// Swatches wrap over as many rows as needed.
+	if len(w.Swatches) > 0 {
+		y += gap
+		cell := theme.ControlH * 2 / 3
+		per := max((r.Dx()+gap)/(cell+gap), 1)
+		for i := range w.Swatches {
+			cx, cy := r.Min.X+i%per*(cell+gap), y+i/per*(cell+gap)
+			l.swatches = append(l.swatches, image.Rect(cx, cy, cx+cell, cy+cell))
+		}
+		y += (len(w.Swatches)+per-1)/per*(cell+gap) - gap
+	}
+	l.height = y - r.Min.Y
+
+	if rtl {
+		flip := func(q image.Rectangle) image.Rectangle {
+			return image.Rect(r.Min.X+r.Max.X-q.Max.X, q.Min.Y, r.Min.X+r.Max.X-q.Min.X, q.Max.Y)
+		}
+		l.sv, l.hue, l.alpha = flip(l.sv), flip(l.hue), flip(l.alpha)
+		l.preview, l.hex = flip(l.preview), flip(l.hex)
+		for i := range l.fields {
+			l.fields[i] = flip(l.fields[i])
+		}
+		for i := range l.swatches {
+			l.swatches[i] = flip(l.swatches[i])
+		}
+	}
+	return l
+}
+
+// dragTo moves the value of the dragged area to p.
+func (w *ColorPicker) dragTo(l colorLayout, p image.Point) {
+	frac := func(v, lo, size int) float64 {
+		if size <= 1 {
+			return 0
+		}
+		return math.Min(math.Max(float64(v-lo)/float64(size-1), 0), 1)
+	}
+
+	switch w.drag {
+	case colorSV:
+		w.s = frac(p.X, l.sv.Min.X, l.sv.Dx())
+		w.v = 1 - frac(p.Y, l.sv.Min.Y, l.sv.Dy())
+	case colorHue:
+		// Kept below 360 so the bottom of the strip stays red, not 0.
+		w.h = math.Min(frac(p.Y, l.hue.Min.Y, l.hue.Dy())*360, 359.9)
+	case colorAlpha:
+		w.a = 1 - frac(p.Y, l.alpha.Min.Y, l.alpha.Dy())
+	}
+	w.changed(nil)
+}
+
+func (w *ColorPicker) Update(ctx *uikit.Context) {
+	r := w.Measure(false)
+	w.SetFrame(r.Min.X, r.Min.Y, r.Dx())
+
+	if w.Compact {
+		w.updateCompact(ctx)
+		return
+	}
+
+	theme := ctx.Theme()
+	l := w.layout(theme, r, w.IsRTL(ctx))
+
+	w.hex.SetFrame(l.hex.Min.X, l.hex.Min.Y, l.hex.Dx())
+	for i, in := range w.inputs()[1:] {
+		in.SetFrame(l.fields[i].Min.X, l.fields[i].Min.Y, l.fields[i].Dx())
+	}
+	for _, in := range w.inputs() {
+		in.Update(ctx)
+	}
+
+	if !w.IsEnabled() {
+		w.drag = colorNone
+		return
+	}
+
+	ptr := ctx.Pointer()
+	if ptr.IsJustDown {
+		switch p := ptr.Position; {
+		case p.In(l.sv):
+			w.drag = colorSV
+		case p.In(l.hue):
+			w.drag = colorHue
+		case w.ShowAlpha && p.In(l.alpha):
+			w.drag = colorAlpha
+		default:
+			for i, sr := range l.swatches {
+				if p.In(sr) {
+					w.SetColor(w.Swatches[i])
+					break
+				}
+			}
+		}
+		if w.drag != colorNone {
+			ctx.CapturePointer(w)
+		}
+	}
+	if w.drag != colorNone && ctx.PointerCapture() == w && (ptr.IsDown || ptr.IsJustUp) {
+		w.dragTo(l, ptr.Position)
+	}
+	if !ptr.IsDown {
+		w.drag = colorNone
+	}
+
+	if !w.IsFocused() {
+		return
+	}
+
+	step := 0.01
+	if shiftPressed() {
+		step = 0.1
+	}
+	switch {
+	case keyRepeat(ebiten.KeyLeft):
+		w.s -= step
+	case keyRepeat(ebiten.KeyRight):
+		w.s += step
+	case keyRepeat(ebiten.KeyUp):
+		w.v += step
+	case keyRepeat(ebiten.KeyDown):
+		w.v -= step
+	case keyRepeat(ebiten.KeyPageUp):
+		w.h = math.Mod(w.h+350, 360)
+	case keyRepeat(ebiten.KeyPageDown):
+		w.h = math.Mod(w.h+10, 360)
+	default:
+		return
+	}
+	w.changed(nil)
+}
+
+func (w *ColorPicker) updateCompact(ctx *uikit.Context) {
+	if !w.IsEnabled() {
+		return
+	}
+
+	ptr := ctx.Pointer()
+	open := ptr.IsJustDown && ptr.Position.In(w.Measure(false))
+	if w.IsFocused() {
+		for _, k := range []ebiten.Key{ebiten.KeyEnter, ebiten.KeyKPEnter, ebiten.KeySpace} {
+			open = open || inpututil.IsKeyJustPressed(k)
+		}
+	}
+	if open {
+		w.openPopup(ctx)
+	}
+}
+
+func (w *ColorPicker) openPopup(ctx *uikit.Context) {
+	if w.popup == nil {
+		w.popup = newColorPopup(w)
+	}
+
+	p := w.popup.picker
+	p.ShowAlpha, p.Swatches = w.ShowAlpha, w.Swatches
+	p.SetDirection(w.Direction())
+	p.h, p.s, p.v, p.a = w.h, w.s, w.v, w.a
+	p.last = p.nrgba()
+	p.syncFields(nil)
+
+	ctx.ShowPopup(w.popup)
+	w.popup.layout(ctx)
+}
+
+// drawSwatch fills r with c over a checkerboard (for translucent colours)
+// and outlines it.
+func (w *ColorPicker) drawSwatch(theme *uikit.Theme, dst *ebiten.Image, r image.Rectangle, c, border color.RGBA) {
+	if c.A < 255 {
+		drawChecker(dst, r, max(theme.ControlH/8, 2))
+	}
+	w.DrawRoundedRect(dst, r, 0, c)
+	w.DrawRoundedBorder(dst, r, 0, theme.BorderW, border)
+}
+
+// markerSize returns the radius of the SV marker and the width of the light
+// ring drawn over a dark halo twice as wide. Strip handles are as tall as the
+// marker radius.
+func markerSize(theme *uikit.Theme) (radius, ring float32) {
+	return float32(max(theme.ControlH/6, 3)), float32(max(theme.FocusRingW, 1))
+}
+
+// drawStripMarker draws the handle across a strip at fraction f from the
+// top, overhanging it by the border width on both sides.
+func drawStripMarker(theme *uikit.Theme, dst *ebiten.Image, r image.Rectangle, f float64) {
+	radius, ring := markerSize(theme)
+	y := float32(r.Min.Y) + float32(f)*float32(r.Dy()-1)
+	x, wd := float32(r.Min.X-theme.BorderW), float32(r.Dx()+theme.BorderW*2)
+	vector.StrokeRect(dst, x, y-radius/2, wd, radius, ring*2, color.RGBA{0, 0, 0, 160}, true)
+	vector.StrokeRect(dst, x, y-radius/2, wd, radius, ring, color.RGBA{255, 255, 255, 255}, true)
+}
+
+func (w *ColorPicker) Draw(ctx *uikit.Context, dst *ebiten.Image) {
+	theme := ctx.Theme()
+	rtl := w.IsRTL(ctx)
+
+	if w.Compact {
+		r := w.Base.Draw(ctx, dst)
+		cy := r.Min.Y + r.Dy()/2
+		size := r.Dy() - theme.SpaceS*2
+
+		x, chevX := r.Min.X+theme.PadX, r.Max.X-theme.PadX
+		if rtl {
+			x, chevX = r.Max.X-theme.PadX-size, r.Min.X+theme.PadX
+		}
+		w.drawSwatch(theme, dst, image.Rect(x, cy-size/2, x+size, cy-size/2+size), w.Color(), theme.BorderColor)
+
+		col := theme.TextColor
+		if !w.IsEnabled() {
+			col = theme.DisabledColor
+		}
+		lx := x + size + theme.SpaceS
+		if rtl {
+			lx = x - theme.SpaceS
+		}
+		drawIconText(theme, dst, nil, w.hexText(), lx, cy, col, rtl)
+		drawDropChevron(theme, dst, chevX, cy, rtl)
+		return
+	}
+
+	r := w.Measure(false)
+	l := w.layout(theme, r, rtl)
+	c := w.nrgba()
+
+	// Square: white to the hue left to right, then darkened to black
+	// towards the bottom.
+	drawGradient(dst, l.sv, color.RGBA{255, 255, 255, 255}, hueColor(w.h), false)
+	drawGradient(dst, l.sv, color.RGBA{}, color.RGBA{0, 0, 0, 255}, true)
+	w.DrawRoundedBorder(dst, l.sv, 0, theme.BorderW, theme.BorderColor)
+
+	for i := range 6 {
+		y0 := l.hue.Min.Y + l.hue.Dy()*i/6
+		y1 := l.hue.Min.Y + l.hue.Dy()*(i+1)/6
+		drawGradient(dst, image.Rect(l.hue.Min.X, y0, l.hue.Max.X, y1), hueColor(float64(i*60)), hueColor(float64((i+1)*60)), true)
+	}
+	w.DrawRoundedBorder(dst, l.hue, 0, theme.BorderW, theme.BorderColor)
+
+	if w.ShowAlpha {
+		drawChecker(dst, l.alpha, max(l.alpha.Dx()/3, 2))
+		drawGradient(dst, l.alpha, color.RGBA{c.R, c.G, c.B, 255}, color.RGBA{}, true)
+		w.DrawRoundedBorder(dst, l.alpha, 0, theme.BorderW, theme.BorderColor)
+	}
+
+	if !w.IsEnabled() {
+		veil := fadeColor(theme.BackgroundColor, 0.5)
+		for _, a := range []image.Rectangle{l.sv, l.hue, l.alpha} {
+			w.DrawRoundedRect(dst, a, 0, veil)
+		}
+	}
+
+	// Markers.
+	mx := float32(l.sv.Min.X) + float32(w.s)*float32(l.sv.Dx()-1)
+	my := float32(l.sv.Min.Y) + float32(1-w.v)*float32(l.sv.Dy()-1)
+	radius, ring := markerSize(theme)
+	vector.StrokeCircle(dst, mx, my, radius, ring*2, color.RGBA{0, 0, 0, 160}, true)
+	vector.StrokeCircle(dst, mx, my, radius, ring, color.RGBA{255, 255, 255, 255}, true)
+	drawStripMarker(theme, dst, l.hue, w.h/360)
+	if w.ShowAlpha {
+		drawStripMarker(theme, dst, l.alpha, 1-w.a)
+	}
+
+	if w.IsFocused() && w.IsEnabled() {
+		w.DrawRoundedBorder(dst, l.sv.Inset(-theme.FocusRingW), 0, theme.FocusRingW, theme.FocusColor)
+	}
+
+	w.drawSwatch(theme, dst, l.preview, w.Color(), theme.BorderColor)
+	for _, in := range w.inputs() {
+		in.Draw(ctx, dst)
+	}
+
+	ptr := ctx.Pointer()
+	cur := w.Color()
+	for i, sr := range l.swatches {
+		border := theme.BorderColor
+		if w.Swatches[i] == cur || (w.IsHovered() && ptr.Position.In(sr)) {
+			border = theme.FocusColor
+		}
+		w.drawSwatch(theme, dst, sr, w.Swatches[i], border)
+	}
+}
+
+// colorPopup shows the full picker of a compact ColorPicker below it. It
+// closes on a click outside, Escape, or Enter on the picker.
+type colorPopup struct {
+	uikit.Base
+
+	owner  *ColorPicker
+	picker *ColorPicker
+	panel  image.Rectangle
+}
+
+func newColorPopup(owner *ColorPicker) *colorPopup {
+	theme := owner.Theme()
+	cfg := uikit.NewWidgetBaseConfig(theme)
+
+	p := &colorPopup{
+		Base:   uikit.NewBase(cfg),
+		owner:  owner,
+		picker: NewColorPicker(theme, owner.Color()),
+	}
+	p.Base.HeightCalculator = func() int { return p.panel.Dy() }
+	p.picker.On(uikit.EventValueChange, p.onPickerChange, false)
+
+	return p
+}
+
+func (p *colorPopup) Focusable() bool { return false }
+
+func (p *colorPopup) Children() []uikit.Widget { return []uikit.Widget{p.picker} }
+
+func (p *colorPopup) InitialFocus() uikit.Widget { return p.picker }
+
+func (p *colorPopup) HitTest(ctx *uikit.Context, pos image.Point) bool {
+	return pos.In(p.panel)
+}
+
+func (p *colorPopup) onPickerChange(uikit.Event) bool {
+	o, k := p.owner, p.picker
+	o.h, o.s, o.v, o.a = k.h, k.s, k.v, k.a
+	o.changed(nil)
+	return false
+}
+
+// layout places the panel below the owner, or above it when there is no
+// room, at least wide enough for a usable square.
+func (p *colorPopup) layout(ctx *uikit.Context) {
+	theme := ctx.Theme()
+	pad := theme.SpaceS
+	a := p.owner.Measure(false)
+
+	w := max(a.Dx(), theme.ControlH*8)
+	p.picker.SetFrame(0, 0, w-pad*2)
+	h := p.picker.Measure(false).Dy() + pad*2
+	size := image.Rect(0, 0, w, h)
+
+	x := a.Min.X
+	if p.owner.IsRTL(ctx) {
+		x = a.Max.X - w
+	}
+	screen := ctx.Screen()
+	below := size.Add(image.Pt(x, a.Max.Y+theme.SpaceS))
+	above := size.Add(image.Pt(x, a.Min.Y-theme.SpaceS-h))
+	p.panel = placeRect(screen, fitRectX(below, screen), fitRectX(above, screen))
+
+	p.SetFrame(p.panel.Min.X, p.panel.Min.Y, w)
+	p.picker.SetFrame(p.panel.Min.X+pad, p.panel.Min.Y+pad, w-pad*2)
+}
+
+func (p *colorPopup) Update(ctx *uikit.Context) {
+	p.layout(ctx)
+	p.picker.Update(ctx)
+
+	ptr := ctx.Pointer()
+	enter := inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyKPEnter)
+	if (ptr.IsJustDown && !ptr.Position.In(p.panel)) || inpututil.IsKeyJustPressed(ebiten.KeyEscape) || (enter && p.picker.IsFocused()) {
+		ctx.ClosePopup(p)
+	}
+}
+
+func (p *colorPopup) Draw(ctx *uikit.Context, dst *ebiten.Image) {
+	theme := ctx.Theme()
+	p.DrawRoundedRect(dst, p.panel, theme.Radius, theme.SurfaceColor)
+	p.DrawRoundedBorder(dst, p.panel, theme.Radius, theme.BorderW, theme.BorderColor)
+	p.picker.Draw(ctx, dst)
+}
+
+func (p *colorPopup) DrawOverlay(ctx *uikit.Context, dst *ebiten.Image) {}
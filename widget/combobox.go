@@ -0,0 +1,395 @@
+package widget
+
+import (
+	"image"
+	"math"
+	"sync"
+
+	"github.com/erparts/go-uikit"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/tinne26/etxt"
+)
+
+var _ uikit.Widget = (*ComboBox)(nil)
+var _ uikit.Hittable = (*ComboBox)(nil)
+var _ uikit.OverlayWidget = (*ComboBox)(nil)
+
+// ComboBox is a TextInput paired with a suggestion dropdown.
+// Suggestions come from a SuggestionProvider and are refreshed on every text change.
+// The dropdown is rendered as an overlay, like the Select list.
+type ComboBox struct {
+	uikit.Base
+
+	input    *TextInput
+	provider SuggestionProvider
+
+	suggestions []Suggestion
+	index       int // highlighted suggestion, -1 means none
+	scroll      int
+	open        bool
+
+	// MaxVisible controls how many suggestions are shown when open.
+	MaxVisible int
+
+	// AllowFreeText accepts any typed text as the value. When false, the text
+	// is reverted to the last accepted suggestion when the widget loses focus.
+	AllowFreeText bool
+
+	value    any
+	hasValue bool
+	accepted string
+
+	// Results may arrive from another goroutine (async providers).
+	mu      sync.Mutex
+	seq     int
+	pending []Suggestion
+	arrived bool
+}
+
+func NewComboBox(theme *uikit.Theme, placeholder string, provider SuggestionProvider) *ComboBox {
+	cfg := uikit.NewWidgetBaseConfig(theme)
+
+	w := &ComboBox{
+		Base:       uikit.NewBase(cfg),
+		input:      NewTextInput(theme, placeholder),
+		provider:   provider,
+		index:      -1,
+		MaxVisible: 5,
+	}
+
+	w.input.On(uikit.EventValueChange, w.onInputChange, false)
+	w.Base.On(uikit.EventFocusLost, w.onFocusLost, false)
+
+	return w
+}
+
+func (w *ComboBox) Focusable() bool     { return true }
+func (w *ComboBox) WantsIME() bool      { return true }
+func (w *ComboBox) OverlayActive() bool { return w.open }
+
+// Text returns the current text of the input.
+func (w *ComboBox) Text() string { return w.input.Text() }
+
+// SetText sets the input text without accepting a suggestion.
+func (w *ComboBox) SetText(s string) { w.input.SetText(s) }
+
+// Value returns the value of the last accepted suggestion.
+// With AllowFreeText, typed text that matches no suggestion is returned as a string.
+func (w *ComboBox) Value() (any, bool) {
+	return w.value, w.hasValue
+}
+
+// SetProvider replaces the suggestion provider.
+func (w *ComboBox) SetProvider(p SuggestionProvider) {
+	w.provider = p
+	w.close()
+}
+
+// Select accepts the given suggestion as if picked from the list.
+func (w *ComboBox) Select(s Suggestion) {
+	w.close()
+	w.input.SetTextSilently(s.Label)
+	w.setValue(s.Label, s.Value, true)
+}
+
+func (w *ComboBox) SetFrame(x, y, width int) {
+	w.Base.SetFrame(x, y, width)
+	w.input.SetFrame(x, y, width)
+}
+
+func (w *ComboBox) SetFocused(v bool) {
+	w.Base.SetFocused(v)
+	w.input.SetFocused(v)
+}
+
+func (w *ComboBox) SetHovered(v bool) {
+	w.Base.SetHovered(v)
+	w.input.SetHovered(v)
+}
+
+func (w *ComboBox) SetPressed(v bool) {
+	w.Base.SetPressed(v)
+	w.input.SetPressed(v)
+}
+
+func (w *ComboBox) SetEnabled(v bool) {
+	w.Base.SetEnabled(v)
+	w.input.SetEnabled(v)
+	if !v {
+		w.close()
+	}
+}
+
+func (w *ComboBox) SetDirection(d uikit.Direction) {
+	w.Base.SetDirection(d)
+	w.input.SetDirection(d)
+}
+
+func (w *ComboBox) SetInvalid(err string) {
+	w.Base.SetInvalid(err)
+	w.input.SetInvalid(err)
+}
+
+func (w *ComboBox) ClearInvalid() {
+	w.Base.ClearInvalid()
+	w.input.ClearInvalid()
+}
+
+func (w *ComboBox) setValue(text string, v any, ok bool) {
+	if w.hasValue == ok && w.accepted == text && w.value == v {
+		return
+	}
+
+	w.accepted = text
+	w.value = v
+	w.hasValue = ok
+	w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange})
+}
+
+func (w *ComboBox) onInputChange(e uikit.Event) bool {
+	text := w.input.Text()
+	if w.AllowFreeText {
+		w.setValue(text, text, text != "")
+	}
+
+	if w.IsFocused() {
+		w.request(text)
+	}
+
+	return false
+}
+
+func (w *ComboBox) onFocusLost(e uikit.Event) bool {
+	w.close()
+
+	text := w.input.Text()
+	if w.AllowFreeText || text == w.accepted {
+		return false
+	}
+
+	// Exact matches typed by hand are accepted on blur.
+	for _, s := range w.suggestions {
+		if s.Label == text {
+			w.setValue(s.Label, s.Value, true)
+			return false
+		}
+	}
+
+	w.input.SetTextSilently(w.accepted)
+	return false
+}
+
+// request asks the provider for suggestions. Stale results are dropped using
+// a sequence number, since async providers may answer out of order.
+func (w *ComboBox) request(query string) {
+	if w.provider == nil {
+		return
+	}
+
+	w.mu.Lock()
+	w.seq++
+	seq := w.seq
+	w.mu.Unlock()
+
+	w.provider.Suggest(query, func(res []Suggestion) {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		if seq != w.seq {
+			return
+		}
+
+		w.pending = res
+		w.arrived = true
+	})
+}
+
+func (w *ComboBox) collect() {
+	w.mu.Lock()
+	res, ok := w.pending, w.arrived
+	w.pending, w.arrived = nil, false
+	w.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	w.suggestions = res
+	w.index = -1
+	w.scroll = 0
+	if len(res) > 0 {
+		w.index = 0
+	}
+
+	w.open = w.IsFocused() && len(res) > 0
+}
+
+func (w *ComboBox) close() {
+	w.open = false
+	w.index = -1
+	w.scroll = 0
+}
+
+func (w *ComboBox) maxVisible() int {
+	if w.MaxVisible <= 0 {
+		return 5
+	}
+	return w.MaxVisible
+}
+
+func (w *ComboBox) clampScroll() {
+	ms := max(len(w.suggestions)-w.maxVisible(), 0)
+	w.scroll = clampInt(w.scroll, 0, ms)
+}
+
+func (w *ComboBox) ensureIndexVisible(idx int) {
+	if idx < w.scroll {
+		w.scroll = idx
+	} else if idx >= w.scroll+w.maxVisible() {
+		w.scroll = idx - w.maxVisible() + 1
+	}
+	w.clampScroll()
+}
+
+func (w *ComboBox) moveIndex(delta int) {
+	if len(w.suggestions) == 0 {
+		return
+	}
+
+	w.index = clampInt(w.index+delta, 0, len(w.suggestions)-1)
+	w.ensureIndexVisible(w.index)
+}
+
+func (w *ComboBox) listRect(ctx *uikit.Context) image.Rectangle {
+	ctrl := w.Measure(false)
+	n := min(max(len(w.suggestions), 1), w.maxVisible())
+
+	listY := ctrl.Max.Y + ctx.Theme().SpaceS
+	return image.Rect(ctrl.Min.X, listY, ctrl.Max.X, listY+(n*ctx.Theme().ControlH))
+}
+
+func (w *ComboBox) HitTest(ctx *uikit.Context, pos image.Point) bool {
+	if pos.In(w.Measure(false)) {
+		return true
+	}
+	if w.open {
+		return pos.In(w.listRect(ctx))
+	}
+	return false
+}
+
+func (w *ComboBox) Update(ctx *uikit.Context) {
+	r := w.Measure(false)
+	if r.Dy() == 0 {
+		w.SetFrame(r.Min.X, r.Min.Y, r.Dx())
+	}
+
+	w.collect()
+
+	if !w.IsEnabled() {
+		return
+	}
+
+	if w.open {
+		ptr := ctx.Pointer()
+		if ptr.IsJustDown {
+			list := w.listRect(ctx)
+			if ptr.Position.In(list) {
+				idx := w.scroll + (ptr.Position.Y-list.Min.Y)/ctx.Theme().ControlH
+				if idx >= 0 && idx < len(w.suggestions) {
+					w.Select(w.suggestions[idx])
+				}
+				return
+			}
+
+			if !ptr.Position.In(r) {
+				w.close()
+			}
+		}
+
+		_, wy := ebiten.Wheel()
+		if wy != 0 {
+			w.scroll -= int(math.Copysign(1, wy))
+			w.clampScroll()
+		}
+	}
+
+	if w.IsFocused() {
+		switch {
+		case inpututil.IsKeyJustPressed(ebiten.KeyDown):
+			if !w.open {
+				w.request(w.input.Text())
+				w.collect()
+			} else {
+				w.moveIndex(1)
+			}
+		case inpututil.IsKeyJustPressed(ebiten.KeyUp):
+			w.moveIndex(-1)
+		case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+			w.close()
+		case inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyKPEnter):
+			// The input blurs itself on Enter; accept the highlighted entry first.
+			if w.open && w.index >= 0 && w.index < len(w.suggestions) {
+				w.Select(w.suggestions[w.index])
+			}
+		}
+	}
+
+	w.input.Update(ctx)
+}
+
+func (w *ComboBox) Draw(ctx *uikit.Context, dst *ebiten.Image) {
+	w.input.Draw(ctx, dst)
+}
+
+func (w *ComboBox) DrawOverlay(ctx *uikit.Context, dst *ebiten.Image) {
+	if !w.open {
+		return
+	}
+
+	theme := ctx.Theme()
+	list := w.listRect(ctx)
+
+	w.Base.DrawRoundedRect(dst, list, theme.Radius, theme.SurfaceColor)
+	w.Base.DrawRoundedBorder(dst, list, theme.Radius, theme.BorderW, theme.BorderColor)
+
+	ptr := ctx.Pointer()
+	visibleRows := list.Dy() / theme.ControlH
+	for i := 0; i < visibleRows; i++ {
+		idx := w.scroll + i
+		if idx >= len(w.suggestions) {
+			break
+		}
+
+		y := list.Min.Y + i*theme.ControlH
+		row := image.Rect(list.Min.X, y, list.Max.X, y+theme.ControlH)
+		if idx == w.index || ptr.Position.In(row) {
+			w.Base.DrawRoundedRect(dst, row, 0, theme.SurfaceHoverColor)
+		}
+
+		w.drawSuggestion(theme, dst, w.suggestions[idx], row, w.IsRTL(ctx))
+	}
+}
+
+// drawSuggestion draws the label in visual order, highlighting the matched
+// ranges with the focus colour.
+func (w *ComboBox) drawSuggestion(theme *uikit.Theme, dst *ebiten.Image, s Suggestion, row image.Rectangle, rtl bool) {
+	t := theme.Text()
+	t.SetAlign(etxt.Left | etxt.VertCenter)
+
+	line := layoutBidi(s.Label, rtl)
+	x := row.Min.X + theme.PadX
+	if rtl {
+		x = row.Max.X - theme.PadX - t.Measure(line.visual).IntWidth()
+	}
+
+	line.drawPieces(t, dst, x, row.Min.Y+row.Dy()/2, func(start int) TextStyle {
+		for _, m := range s.Match {
+			if start >= m[0] && start < m[1] {
+				return TextStyle{Color: theme.FocusColor}
+			}
+		}
+		return TextStyle{Color: theme.TextColor}
+	})
+}
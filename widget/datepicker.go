@@ -0,0 +1,561 @@
+package widget
+
+import (
+	"image"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/erparts/go-uikit"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/tinne26/etxt"
+)
+
+var _ uikit.Widget = (*DatePicker)(nil)
+var _ uikit.Hittable = (*DatePicker)(nil)
+var _ uikit.OverlayWidget = (*DatePicker)(nil)
+var _ uikit.ValidableWidget = (*DatePicker)(nil)
+
+// DatePicker is a date field with a calendar drop-down.
+// - The text is parsed with Layout as it is typed. Dates that do not parse,
+// fall outside Min/Max or are Disabled mark the widget invalid and leave
+// the value unchanged.
+// - Down or a click on the chevron opens the calendar below the field, drawn
+// as an overlay like the Select list. While it is open the arrows move the
+// highlighted day, PageUp/PageDown change the month (the year with Shift),
+// Home/End go to the start and end of the week, Enter or Space picks and
+// Escape closes.
+// - WeekStart is the first calendar column; WeekStartFor gives the one
+// customary for a locale.
+// - MonthNames and WeekdayNames label the calendar; they default to English
+// and can be replaced for other languages.
+// - EventValueChange carries the new time.Time, zero when cleared.
+type DatePicker struct {
+	uikit.Base
+
+	input *TextInput
+
+	// Layout formats and parses the text (see time.Parse).
+	Layout string
+	// Min and Max bound the dates that can be picked; zero means no bound.
+	Min, Max time.Time
+	// Disabled reports dates that cannot be picked (e.g. holidays). May be nil.
+	Disabled  func(time.Time) bool
+	WeekStart time.Weekday
+	// MonthNames[m-1] titles month m; WeekdayNames[d] heads the column of
+	// weekday d (indexed by time.Weekday, Sunday first).
+	MonthNames   [12]string
+	WeekdayNames [7]string
+
+	value    time.Time
+	hasValue bool
+	loc      *time.Location
+
+	open  bool
+	month time.Time // first day of the month shown
+	hot   time.Time // day highlighted by the keyboard
+}
+
+// calendarLayout holds the calendar rectangles, in screen coordinates.
+type calendarLayout struct {
+	panel, title, prev, next image.Rectangle
+	weekdays                 [7]image.Rectangle
+	days                     [42]image.Rectangle
+	first                    time.Time // date in days[0]
+}
+
+// NewDatePicker creates an empty date field. Dates default to the ISO
+// layout and weeks to Monday.
+func NewDatePicker(theme *uikit.Theme) *DatePicker {
+	cfg := uikit.NewWidgetBaseConfig(theme)
+
+	w := &DatePicker{
+		Base:      uikit.NewBase(cfg),
+		input:     NewTextInput(theme, "YYYY-MM-DD"),
+		Layout:    time.DateOnly,
+		WeekStart: time.Monday,
+		loc:       time.Local,
+
+		MonthNames: [12]string{"January", "February", "March", "April", "May", "June",
+			"July", "August", "September", "October", "November", "December"},
+		WeekdayNames: [7]string{"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"},
+	}
+
+	w.input.On(uikit.EventValueChange, w.onInputChange, false)
+	w.Base.On(uikit.EventFocusLost, w.onFocusLost, false)
+
+	return w
+}
+
+func (w *DatePicker) Focusable() bool     { return true }
+func (w *DatePicker) WantsIME() bool      { return true }
+func (w *DatePicker) OverlayActive() bool { return w.open }
+func (w *DatePicker) IsValidable() bool   { return true }
+
+// handlesEnter: Enter picks the highlighted day while the calendar is open.
+func (w *DatePicker) handlesEnter() bool { return w.open }
+
+func (w *DatePicker) SetPlaceholder(p string) { w.input.SetPlaceholder(p) }
+
+// Text returns the current text of the field.
+func (w *DatePicker) Text() string { return w.input.Text() }
+
+// Value returns the picked date, at midnight, and whether there is one.
+func (w *DatePicker) Value() (time.Time, bool) {
+	return w.value, w.hasValue
+}
+
+// SetValue sets the date, dropping the time of day. Its location is used
+// for dates typed or picked later.
+func (w *DatePicker) SetValue(t time.Time) {
+	w.loc = t.Location()
+	w.input.SetTextSilently(t.Format(w.Layout))
+	w.parse()
+}
+
+// Clear empties the field.
+func (w *DatePicker) Clear() {
+	w.input.SetTextSilently("")
+	w.parse()
+}
+
+func (w *DatePicker) SetFrame(x, y, width int) {
+	w.Base.SetFrame(x, y, width)
+	w.input.SetFrame(x, y, width)
+}
+
+func (w *DatePicker) SetFocused(v bool) {
+	w.Base.SetFocused(v)
+	w.input.SetFocused(v)
+}
+
+func (w *DatePicker) SetHovered(v bool) {
+	w.Base.SetHovered(v)
+	w.input.SetHovered(v)
+}
+
+func (w *DatePicker) SetPressed(v bool) {
+	w.Base.SetPressed(v)
+	w.input.SetPressed(v)
+}
+
+func (w *DatePicker) SetEnabled(v bool) {
+	w.Base.SetEnabled(v)
+	w.input.SetEnabled(v)
+	if !v {
+		w.open = false
+	}
+}
+
+func (w *DatePicker) SetDirection(d uikit.Direction) {
+	w.Base.SetDirection(d)
+	w.input.SetDirection(d)
+}
+
+func (w *DatePicker) SetInvalid(err string) {
+	w.Base.SetInvalid(err)
+	w.input.SetInvalid(err)
+}
+
+func (w *DatePicker) ClearInvalid() {
+	w.Base.ClearInvalid()
+	w.input.ClearInvalid()
+}
+
+// dateOf returns midnight of the day of t, in its location.
+func dateOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// dayNumber orders dates by calendar day, whatever their location.
+func dayNumber(t time.Time) int {
+	y, m, d := t.Date()
+	return y*10000 + int(m)*100 + d
+}
+
+// addMonths moves t by n months, keeping the day within the target month.
+func addMonths(t time.Time, n int) time.Time {
+	y, m, d := t.Date()
+	last := time.Date(y, m+time.Month(n)+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	return time.Date(y, m+time.Month(n), min(d, last), 0, 0, 0, 0, t.Location())
+}
+
+// check returns why d cannot be picked, or "" if it can.
+func (w *DatePicker) check(d time.Time) string {
+	switch {
+	case !w.Min.IsZero() && dayNumber(d) < dayNumber(w.Min):
+		return "Date before " + w.Min.Format(w.Layout)
+	case !w.Max.IsZero() && dayNumber(d) > dayNumber(w.Max):
+		return "Date after " + w.Max.Format(w.Layout)
+	case w.Disabled != nil && w.Disabled(d):
+		return "Date not available"
+	}
+	return ""
+}
+
+// parse validates the text and takes it as the value when it is a date
+// that can be picked.
+func (w *DatePicker) parse() {
+	text := strings.TrimSpace(w.input.Text())
+	if text == "" {
+		w.ClearInvalid()
+		w.setValue(time.Time{}, false)
+		return
+	}
+
+	t, err := time.ParseInLocation(w.Layout, text, w.loc)
+	if err != nil {
+		w.SetInvalid("Invalid date")
+		return
+	}
+
+	t = dateOf(t)
+	if msg := w.check(t); msg != "" {
+		w.SetInvalid(msg)
+		return
+	}
+
+	w.ClearInvalid()
+	w.setValue(t, true)
+}
+
+func (w *DatePicker) setValue(t time.Time, ok bool) {
+	if ok == w.hasValue && t.Equal(w.value) {
+		return
+	}
+
+	w.value, w.hasValue = t, ok
+	w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange, Value: t})
+}
+
+func (w *DatePicker) onInputChange(uikit.Event) bool {
+	w.parse()
+	return false
+}
+
+// onFocusLost closes the calendar and writes a valid date back in the
+// canonical layout.
+func (w *DatePicker) onFocusLost(uikit.Event) bool {
+	w.open = false
+	if ok, _ := w.IsInvalid(); !ok && w.hasValue {
+		w.input.SetTextSilently(w.value.Format(w.Layout))
+	}
+	return false
+}
+
+func (w *DatePicker) pick(d time.Time) {
+	w.input.SetTextSilently(d.Format(w.Layout))
+	w.parse()
+	w.open = false
+}
+
+func (w *DatePicker) openCalendar(ctx *uikit.Context) {
+	d := ctx.Now().In(w.loc)
+	if w.hasValue {
+		d = w.value
+	}
+
+	w.open = true
+	w.moveHot(dateOf(d))
+}
+
+func (w *DatePicker) moveHot(d time.Time) {
+	w.hot = d
+	w.month = d.AddDate(0, 0, 1-d.Day())
+}
+
+// chevronRect is the square on the trailing side that toggles the calendar.
+func (w *DatePicker) chevronRect(theme *uikit.Theme, r image.Rectangle, rtl bool) image.Rectangle {
+	if rtl {
+		return image.Rect(r.Min.X, r.Min.Y, r.Min.X+theme.ControlH, r.Max.Y)
+	}
+	return image.Rect(r.Max.X-theme.ControlH, r.Min.Y, r.Max.X, r.Max.Y)
+}
+
+// calendar places the calendar below the field, or above it when there is
+// no room. Columns run right to left in RTL.
+func (w *DatePicker) calendar(ctx *uikit.Context) calendarLayout {
+	theme := ctx.Theme()
+	r := w.Measure(false)
+	rtl := w.IsRTL(ctx)
+	cell := theme.ControlH
+	pad := theme.SpaceS
+	headH := theme.ControlH * 2 / 3
+
+	size := image.Rect(0, 0, 7*cell+pad*2, cell+headH+6*cell+pad*2)
+	x := r.Min.X
+	if rtl {
+		x = r.Max.X - size.Dx()
+	}
+	screen := ctx.Screen()
+	below := size.Add(image.Pt(x, r.Max.Y+theme.SpaceS))
+	above := size.Add(image.Pt(x, r.Min.Y-theme.SpaceS-size.Dy()))
+
+	var l calendarLayout
+	l.panel = placeRect(screen, fitRectX(below, screen), fitRectX(above, screen))
+
+	in := l.panel.Inset(pad)
+	l.prev = image.Rect(in.Min.X, in.Min.Y, in.Min.X+cell, in.Min.Y+cell)
+	l.next = image.Rect(in.Max.X-cell, in.Min.Y, in.Max.X, in.Min.Y+cell)
+	if rtl {
+		l.prev, l.next = l.next, l.prev
+	}
+	l.title = image.Rect(in.Min.X+cell, in.Min.Y, in.Max.X-cell, in.Min.Y+cell)
+
+	col := func(i int) int {
+		if rtl {
+			i = 6 - i
+		}
+		return in.Min.X + i*cell
+	}
+	y := in.Min.Y + cell
+	for i := range l.weekdays {
+		l.weekdays[i] = image.Rect(col(i), y, col(i)+cell, y+headH)
+	}
+	y += headH
+	for i := range l.days {
+		cx, cy := col(i%7), y+i/7*cell
+		l.days[i] = image.Rect(cx, cy, cx+cell, cy+cell)
+	}
+
+	off := (int(w.month.Weekday()) - int(w.WeekStart) + 7) % 7
+	l.first = w.month.AddDate(0, 0, -off)
+	return l
+}
+
+func (w *DatePicker) HitTest(ctx *uikit.Context, pos image.Point) bool {
+	if pos.In(w.Measure(false)) {
+		return true
+	}
+	return w.open && pos.In(w.calendar(ctx).panel)
+}
+
+func (w *DatePicker) Update(ctx *uikit.Context) {
+	r := w.Measure(false)
+	if r.Dy() == 0 {
+		w.SetFrame(r.Min.X, r.Min.Y, r.Dx())
+	}
+
+	if !w.IsEnabled() {
+		w.open = false
+		return
+	}
+
+	rtl := w.IsRTL(ctx)
+	ptr := ctx.Pointer()
+	if p := ptr.Position; ptr.IsJustDown {
+		if l := w.calendar(ctx); w.open && p.In(l.panel) {
+			w.calendarClick(l, p)
+			return
+		}
+
+		if p.In(w.chevronRect(ctx.Theme(), r, rtl)) {
+			if w.open {
+				w.open = false
+			} else {
+				w.openCalendar(ctx)
+			}
+			return
+		}
+		w.open = false
+	}
+
+	// While the calendar is open it has the keyboard.
+	if w.open {
+		if w.IsFocused() {
+			w.updateCalendarKeys(rtl)
+		}
+		return
+	}
+
+	if w.IsFocused() && inpututil.IsKeyJustPressed(ebiten.KeyDown) {
+		w.openCalendar(ctx)
+		return
+	}
+
+	w.input.Update(ctx)
+}
+
+func (w *DatePicker) calendarClick(l calendarLayout, p image.Point) {
+	switch {
+	case p.In(l.prev):
+		w.moveHot(addMonths(w.hot, -1))
+	case p.In(l.next):
+		w.moveHot(addMonths(w.hot, 1))
+	default:
+		for i, c := range l.days {
+			if d := l.first.AddDate(0, 0, i); p.In(c) && w.check(d) == "" {
+				w.pick(d)
+				return
+			}
+		}
+	}
+}
+
+func (w *DatePicker) updateCalendarKeys(rtl bool) {
+	prev, next := ebiten.KeyLeft, ebiten.KeyRight
+	if rtl {
+		prev, next = next, prev
+	}
+
+	months := 1
+	if shiftPressed() {
+		months = 12
+	}
+	weekday := (int(w.hot.Weekday()) - int(w.WeekStart) + 7) % 7
+
+	switch {
+	case keyRepeat(next):
+		w.moveHot(w.hot.AddDate(0, 0, 1))
+	case keyRepeat(prev):
+		w.moveHot(w.hot.AddDate(0, 0, -1))
+	case keyRepeat(ebiten.KeyDown):
+		w.moveHot(w.hot.AddDate(0, 0, 7))
+	case keyRepeat(ebiten.KeyUp):
+		w.moveHot(w.hot.AddDate(0, 0, -7))
+	case keyRepeat(ebiten.KeyPageDown):
+		w.moveHot(addMonths(w.hot, months))
+	case keyRepeat(ebiten.KeyPageUp):
+		w.moveHot(addMonths(w.hot, -months))
+	case inpututil.IsKeyJustPressed(ebiten.KeyHome):
+		w.moveHot(w.hot.AddDate(0, 0, -weekday))
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnd):
+		w.moveHot(w.hot.AddDate(0, 0, 6-weekday))
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyKPEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace):
+		if w.check(w.hot) == "" {
+			w.pick(w.hot)
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		w.open = false
+	}
+}
+
+func (w *DatePicker) Draw(ctx *uikit.Context, dst *ebiten.Image) {
+	w.input.Draw(ctx, dst)
+
+	theme := ctx.Theme()
+	r := w.Measure(false)
+	x := r.Max.X - theme.PadX
+	if w.IsRTL(ctx) {
+		x = r.Min.X + theme.PadX
+	}
+	drawDropChevron(theme, dst, x, r.Min.Y+r.Dy()/2, w.IsRTL(ctx))
+}
+
+// DrawOverlay draws the calendar.
+func (w *DatePicker) DrawOverlay(ctx *uikit.Context, dst *ebiten.Image) {
+	if !w.open {
+		return
+	}
+
+	theme := ctx.Theme()
+	rtl := w.IsRTL(ctx)
+	l := w.calendar(ctx)
+	ptr := ctx.Pointer()
+
+	w.DrawRoundedRect(dst, l.panel, theme.Radius, theme.SurfaceColor)
+	w.DrawRoundedBorder(dst, l.panel, theme.Radius, theme.BorderW, theme.BorderColor)
+
+	// Header: month navigation around the title; the arrows point outwards.
+	dir := float32(1)
+	if rtl {
+		dir = -1
+	}
+	for _, b := range []struct {
+		r   image.Rectangle
+		dir float32
+	}{{l.prev, -dir}, {l.next, dir}} {
+		if ptr.Position.In(b.r) {
+			w.DrawRoundedRect(dst, b.r, theme.Radius, theme.SurfaceHoverColor)
+		}
+		cx, cy := float32(b.r.Min.X+b.r.Dx()/2), float32(b.r.Min.Y+b.r.Dy()/2)
+		drawChevron(dst, cx, cy, float32(theme.IconSize), b.dir, 0, theme.TextColor)
+	}
+
+	t := theme.Text()
+	t.SetAlign(etxt.Center)
+	t.SetColor(theme.TextColor)
+	t.Draw(dst, w.MonthNames[w.month.Month()-1]+" "+strconv.Itoa(w.month.Year()), l.title.Min.X+l.title.Dx()/2, l.title.Min.Y+l.title.Dy()/2)
+
+	t.SetColor(theme.MutedTextColor)
+	for i, c := range l.weekdays {
+		wd := time.Weekday((int(w.WeekStart) + i) % 7)
+		t.Draw(dst, w.WeekdayNames[wd], c.Min.X+c.Dx()/2, c.Min.Y+c.Dy()/2)
+	}
+
+	today := dayNumber(ctx.Now().In(w.loc))
+	for i, c := range l.days {
+		d := l.first.AddDate(0, 0, i)
+		box := c.Inset(theme.BorderW)
+		ok := w.check(d) == ""
+
+		col := theme.TextColor
+		switch {
+		case !ok:
+			col = theme.DisabledColor
+		case d.Month() != w.month.Month():
+			col = theme.MutedTextColor
+		}
+
+		switch {
+		case w.hasValue && dayNumber(d) == dayNumber(w.value):
+			w.DrawRoundedRect(dst, box, theme.Radius, theme.FocusColor)
+			col = theme.BackgroundColor
+		case ok && ptr.Position.In(c):
+			w.DrawRoundedRect(dst, box, theme.Radius, theme.SurfaceHoverColor)
+		}
+
+		switch {
+		case dayNumber(d) == dayNumber(w.hot) && w.IsFocused():
+			w.DrawRoundedBorder(dst, box, theme.Radius, theme.FocusRingW, theme.FocusColor)
+		case dayNumber(d) == today:
+			w.DrawRoundedBorder(dst, box, theme.Radius, theme.BorderW, theme.BorderColor)
+		}
+
+		t.SetColor(col)
+		t.Draw(dst, strconv.Itoa(d.Day()), c.Min.X+c.Dx()/2, c.Min.Y+c.Dy()/2)
+	}
+}
+
+// weekStartRegions lists the regions whose week does not start on Monday
+// (CLDR).
+var weekStartRegions = map[string]time.Weekday{
+	"AG": time.Sunday, "AS": time.Sunday, "BD": time.Sunday, "BR": time.Sunday,
+	"BS": time.Sunday, "BT": time.Sunday, "BW": time.Sunday, "BZ": time.Sunday,
+	"CA": time.Sunday, "CN": time.Sunday, "CO": time.Sunday, "DM": time.Sunday,
+	"DO": time.Sunday, "ET": time.Sunday, "GT": time.Sunday, "GU": time.Sunday,
+	"HK": time.Sunday, "HN": time.Sunday, "ID": time.Sunday, "IL": time.Sunday,
+	"IN": time.Sunday, "JM": time.Sunday, "JP": time.Sunday, "KE": time.Sunday,
+	"KH": time.Sunday, "KR": time.Sunday, "LA": time.Sunday, "MH": time.Sunday,
+	"MM": time.Sunday, "MO": time.Sunday, "MT": time.Sunday, "MX": time.Sunday,
+	"MZ": time.Sunday, "NI": time.Sunday, "NP": time.Sunday, "PA": time.Sunday,
+	"PE": time.Sunday, "PH": time.Sunday, "PK": time.Sunday, "PR": time.Sunday,
+	"PT": time.Sunday, "PY": time.Sunday, "SA": time.Sunday, "SG": time.Sunday,
+	"SV": time.Sunday, "TH": time.Sunday, "TT": time.Sunday, "TW": time.Sunday,
+	"UM": time.Sunday, "US": time.Sunday, "VE": time.Sunday, "VI": time.Sunday,
+	"WS": time.Sunday, "YE": time.Sunday, "ZA": time.Sunday, "ZW": time.Sunday,
+	"AE": time.Saturday, "AF": time.Saturday, "BH": time.Saturday, "DJ": time.Saturday,
+	"DZ": time.Saturday, "EG": time.Saturday, "IQ": time.Saturday, "IR": time.Saturday,
+	"JO": time.Saturday, "KW": time.Saturday, "LY": time.Saturday, "OM": time.Saturday,
+	"QA": time.Saturday, "SD": time.Saturday, "SY": time.Saturday,
+	"MV": time.Friday,
+}
+
+// WeekStartFor returns the first day of the week customary for a locale
+// such as "en-US" or "ar_EG", from its region. Locales without a region or
+// with an unlisted one start on Monday (ISO 8601).
+func WeekStartFor(locale string) time.Weekday {
+	parts := strings.FieldsFunc(locale, func(r rune) bool { return r == '-' || r == '_' })
+	for _, p := range parts[min(1, len(parts)):] {
+		if len(p) != 2 {
+			continue // script or variant subtag
+		}
+		if d, ok := weekStartRegions[strings.ToUpper(p)]; ok {
+			return d
+		}
+		break
+	}
+	return time.Monday
+}
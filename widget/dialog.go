@@ -0,0 +1,337 @@
+package widget
+
+import (
+	"image"
+
+	"github.com/erparts/go-uikit"
+	"github.com/erparts/go-uikit/layout"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/tinne26/etxt"
+)
+
+var _ uikit.ModalWidget = (*Dialog)(nil)
+
+// DialogResult identifies the button that closed a Dialog. Custom buttons
+// may use any other value.
+type DialogResult int
+
+const (
+	// DialogCancel is also the result of closing with Escape.
+	DialogCancel DialogResult = iota
+	DialogOK
+)
+
+// Dialog is a modal panel: a title, a body layout and a row of buttons.
+// - Show it with Context.ShowDialog; it is centred over a dimmed backdrop
+// and owns all input until closed.
+// - Buttons sit on the trailing side, the last added at the edge.
+// - Escape closes with DialogCancel; Enter triggers the default button
+// unless the focused widget uses Enter itself (a button, a TextArea...).
+// - OnClose receives the result once the dialog has left the screen.
+type Dialog struct {
+	uikit.Base
+
+	title   string
+	body    uikit.Layout
+	buttons []*Button
+	results []DialogResult
+	def     int // default button, -1 if none
+
+	// Width of the panel. 0 picks one from the screen size.
+	Width int
+
+	OnClose func(result DialogResult)
+
+	closing bool
+	result  DialogResult
+	height  int
+}
+
+// NewDialog creates a dialog around body, which may be nil.
+func NewDialog(theme *uikit.Theme, title string, body uikit.Layout) *Dialog {
+	cfg := uikit.NewWidgetBaseConfig(theme)
+	cfg.DrawFocus = false
+
+	d := &Dialog{
+		Base:  uikit.NewBase(cfg),
+		title: title,
+		body:  body,
+		def:   -1,
+	}
+	d.Base.HeightCalculator = func() int { return d.height }
+
+	return d
+}
+
+func (d *Dialog) Focusable() bool { return false }
+
+func (d *Dialog) SetTitle(s string) { d.title = s }
+
+func (d *Dialog) Body() uikit.Layout { return d.body }
+
+// AddButton appends a button closing the dialog with result.
+func (d *Dialog) AddButton(label string, result DialogResult) *Button {
+	b := NewButton(d.Theme(), label)
+	b.OnClick = func() { d.Close(result) }
+
+	d.buttons = append(d.buttons, b)
+	d.results = append(d.results, result)
+	return b
+}
+
+// SetDefault makes the button with result the one triggered by Enter.
+func (d *Dialog) SetDefault(result DialogResult) {
+	d.def = -1
+	for i, r := range d.results {
+		if r == result {
+			d.def = i
+			return
+		}
+	}
+}
+
+// Close closes the dialog with result on its next Update.
+func (d *Dialog) Close(result DialogResult) {
+	if d.closing {
+		return
+	}
+	d.closing, d.result = true, result
+}
+
+func (d *Dialog) Children() []uikit.Widget {
+	var out []uikit.Widget
+	if d.body != nil {
+		out = append(out, d.body)
+	}
+	for _, b := range d.buttons {
+		out = append(out, b)
+	}
+	return out
+}
+
+// InitialFocus picks the first focusable body widget, or the default button.
+func (d *Dialog) InitialFocus() uikit.Widget {
+	if d.body != nil {
+		if w := firstFocusable(d.body); w != nil {
+			return w
+		}
+	}
+	if d.def >= 0 {
+		return d.buttons[d.def]
+	}
+	return nil
+}
+
+func firstFocusable(w uikit.Widget) uikit.Widget {
+	if w.IsVisible() && w.IsEnabled() && w.Focusable() {
+		return w
+	}
+
+	if l, ok := any(w).(interface{ Children() []uikit.Widget }); ok {
+		for _, ch := range l.Children() {
+			if f := firstFocusable(ch); f != nil {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// overlayOpen reports whether a widget of the subtree shows an overlay
+// (which then gets Escape and Enter first).
+func overlayOpen(w uikit.Widget) bool {
+	if o, ok := any(w).(uikit.OverlayWidget); ok && o.OverlayActive() {
+		return true
+	}
+
+	if l, ok := any(w).(interface{ Children() []uikit.Widget }); ok {
+		for _, ch := range l.Children() {
+			if overlayOpen(ch) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (d *Dialog) buttonWidth(theme *uikit.Theme, b *Button) int {
+	return max(b.PreferredWidth(), theme.ControlH*3)
+}
+
+// layout centres the panel on the screen and places the body and buttons.
+func (d *Dialog) layout(ctx *uikit.Context) {
+	theme := ctx.Theme()
+	screen := ctx.Screen()
+	pad := theme.SpaceM
+
+	width := d.Width
+	if width <= 0 {
+		width = min(screen.Dx()-theme.SpaceL*2, theme.ControlH*14)
+	}
+	inner := max(width-pad*2, 0)
+
+	bodyH := 0
+	if d.body != nil {
+		bodyH = d.body.Measure(true).Dy() + theme.SpaceS
+	}
+	d.height = pad*2 + theme.ControlH*2 + theme.SpaceM + bodyH
+
+	x := screen.Min.X + (screen.Dx()-width)/2
+	y := screen.Min.Y + max((screen.Dy()-d.height)/2, 0)
+	d.SetFrame(x, y, width)
+
+	if d.body != nil {
+		d.body.SetFrame(x+pad, y+pad+theme.ControlH+theme.SpaceS, inner)
+	}
+
+	// Buttons from the trailing edge, the last one outermost.
+	rtl := d.IsRTL(ctx)
+	by := y + d.height - pad - theme.ControlH
+	edge := 0
+	for i := len(d.buttons) - 1; i >= 0; i-- {
+		b := d.buttons[i]
+		bw := d.buttonWidth(theme, b)
+
+		bx := x + width - pad - edge - bw
+		if rtl {
+			bx = x + pad + edge
+		}
+		b.SetFrame(bx, by, bw)
+		edge += bw + theme.SpaceS
+	}
+}
+
+func (d *Dialog) Update(ctx *uikit.Context) {
+	if ctx.Screen().Empty() {
+		return
+	}
+	d.layout(ctx)
+
+	overlay := d.body != nil && overlayOpen(d.body)
+	if !overlay {
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			d.Close(DialogCancel)
+		}
+
+		enter := inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyKPEnter)
+		if enter && d.def >= 0 && !usesEnter(ctx.Focused()) {
+			d.Close(d.results[d.def])
+		}
+	}
+
+	if !d.closing {
+		if d.body != nil {
+			d.body.Update(ctx)
+		}
+		for _, b := range d.buttons {
+			b.Update(ctx)
+		}
+	}
+
+	if d.closing {
+		d.closing = false
+		ctx.CloseDialog(d)
+		if d.OnClose != nil {
+			d.OnClose(d.result)
+		}
+	}
+}
+
+func (d *Dialog) Draw(ctx *uikit.Context, dst *ebiten.Image) {
+	r := d.Base.Draw(ctx, dst)
+	theme := ctx.Theme()
+	rtl := d.IsRTL(ctx)
+	pad := theme.SpaceM
+
+	title := image.Rect(r.Min.X+pad, r.Min.Y+pad, r.Max.X-pad, r.Min.Y+pad+theme.ControlH)
+	t := theme.Text()
+	t.SetColor(theme.TextColor)
+	if rtl {
+		t.SetAlign(etxt.Right | etxt.VertCenter)
+		t.Draw(dst, visualText(d.title, rtl), title.Max.X, title.Min.Y+title.Dy()/2)
+	} else {
+		t.SetAlign(etxt.Left | etxt.VertCenter)
+		t.Draw(dst, d.title, title.Min.X, title.Min.Y+title.Dy()/2)
+	}
+
+	if d.body != nil {
+		d.body.Draw(ctx, dst)
+	}
+
+	for i, b := range d.buttons {
+		b.Draw(ctx, dst)
+		if i == d.def && !b.IsFocused() {
+			d.DrawRoundedBorder(dst, b.Measure(false), theme.Radius, theme.BorderW, theme.FocusColor)
+		}
+	}
+}
+
+func (d *Dialog) DrawOverlay(ctx *uikit.Context, dst *ebiten.Image) {
+	if d.body != nil {
+		d.body.DrawOverlay(ctx, dst)
+	}
+}
+
+// messageBody returns a body holding message, or an empty one.
+func messageBody(theme *uikit.Theme, message string) *layout.Stack {
+	body := layout.NewStack(theme)
+	if message != "" {
+		body.Add(NewLabel(theme, message))
+	}
+	return body
+}
+
+// Alert shows a message with an OK button. onClose may be nil.
+func Alert(ctx *uikit.Context, title, message string, onClose func()) *Dialog {
+	d := NewDialog(ctx.Theme(), title, messageBody(ctx.Theme(), message))
+	d.AddButton("OK", DialogOK)
+	d.SetDefault(DialogOK)
+	d.OnClose = func(DialogResult) {
+		if onClose != nil {
+			onClose()
+		}
+	}
+
+	ctx.ShowDialog(d)
+	return d
+}
+
+// Confirm asks a yes/no question with Cancel and OK buttons.
+func Confirm(ctx *uikit.Context, title, message string, onClose func(ok bool)) *Dialog {
+	d := NewDialog(ctx.Theme(), title, messageBody(ctx.Theme(), message))
+	d.AddButton("Cancel", DialogCancel)
+	d.AddButton("OK", DialogOK)
+	d.SetDefault(DialogOK)
+	d.OnClose = func(r DialogResult) {
+		if onClose != nil {
+			onClose(r == DialogOK)
+		}
+	}
+
+	ctx.ShowDialog(d)
+	return d
+}
+
+// Prompt asks for a line of text, starting from value. Enter in the input
+// accepts it.
+func Prompt(ctx *uikit.Context, title, message, value string, onClose func(text string, ok bool)) *Dialog {
+	theme := ctx.Theme()
+	body := messageBody(theme, message)
+	input := NewTextInput(theme, "")
+	input.SetText(value)
+	body.Add(input)
+
+	d := NewDialog(theme, title, body)
+	d.AddButton("Cancel", DialogCancel)
+	d.AddButton("OK", DialogOK)
+	d.SetDefault(DialogOK)
+	d.OnClose = func(r DialogResult) {
+		if onClose != nil {
+			onClose(input.Text(), r == DialogOK)
+		}
+	}
+
+	ctx.ShowDialog(d)
+	return d
+}
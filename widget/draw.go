@@ -0,0 +1,193 @@
+package widget
+
+import (
+	"image"
+	"image/color"
+	"time"
+
+	"github.com/erparts/go-uikit"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/tinne26/etxt"
+)
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// drawTextPiece draws s at (x, y) with the given colour and returns the x
+// position where the next piece should start.
+func drawTextPiece(t *etxt.Renderer, dst *ebiten.Image, s string, x, y int, col color.RGBA) int {
+	if s == "" {
+		return x
+	}
+
+	t.SetColor(col)
+	t.Draw(dst, s, x, y)
+	return x + t.Measure(s).IntWidth()
+}
+
+// approach moves cur towards target so that a full 0..1 transition takes d.
+func approach(cur, target float64, dt, d time.Duration) float64 {
+	if d <= 0 || dt >= d {
+		return target
+	}
+
+	step := float64(dt) / float64(d)
+	if cur < target {
+		return min(cur+step, target)
+	}
+	return max(cur-step, target)
+}
+
+// lerpColor blends a and b; f = 0 gives a, f = 1 gives b.
+func lerpColor(a, b color.RGBA, f float64) color.RGBA {
+	mix := func(x, y uint8) uint8 { return uint8(float64(x) + (float64(y)-float64(x))*f + 0.5) }
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), mix(a.A, b.A)}
+}
+
+// fadeColor scales the (premultiplied) colour by a.
+func fadeColor(c color.RGBA, a float64) color.RGBA {
+	f := func(v uint8) uint8 { return uint8(float64(v)*a + 0.5) }
+	return color.RGBA{f(c.R), f(c.G), f(c.B), f(c.A)}
+}
+
+// drawChevron strokes a chevron of the given size centred on (cx, cy),
+// pointing along the unit direction (dx, dy), e.g. (1, 0) for right.
+func drawChevron(dst *ebiten.Image, cx, cy, size, dx, dy float32, col color.RGBA) {
+	a, b := size/4, size/2 // half length along the direction, half width
+	px, py := -dy, dx
+	tipX, tipY := cx+dx*a, cy+dy*a
+	vector.StrokeLine(dst, cx-dx*a+px*b, cy-dy*a+py*b, tipX, tipY, 2, col, true)
+	vector.StrokeLine(dst, tipX, tipY, cx-dx*a-px*b, cy-dy*a-py*b, 2, col, true)
+}
+
+// drawIconText draws an optional icon followed by s, starting at x on the
+// leading side (x is the right edge in RTL), vertically centred on cy.
+func drawIconText(theme *uikit.Theme, dst *ebiten.Image, icon *uikit.Icon, s string, x, cy int, col color.RGBA, rtl bool) {
+	if icon != nil {
+		size := theme.IconSize
+		ix := x
+		if rtl {
+			ix = x - size
+		}
+		icon.Draw(dst, image.Rect(ix, cy-size/2, ix+size, cy-size/2+size), col)
+
+		if rtl {
+			x -= size + theme.SpaceS
+		} else {
+			x += size + theme.SpaceS
+		}
+	}
+
+	if s == "" {
+		return
+	}
+
+	t := theme.Text()
+	t.SetColor(col)
+	if rtl {
+		t.SetAlign(etxt.Right | etxt.VertCenter)
+	} else {
+		t.SetAlign(etxt.Left | etxt.VertCenter)
+	}
+	t.Draw(dst, visualText(s, rtl), x, cy)
+}
+
+// iconTextWidth returns the width drawIconText needs.
+func iconTextWidth(theme *uikit.Theme, icon *uikit.Icon, s string) int {
+	w := 0
+	if s != "" {
+		w = theme.Text().Measure(s).IntWidth()
+	}
+	if icon != nil {
+		w += theme.IconSize
+		if s != "" {
+			w += theme.SpaceS
+		}
+	}
+	return w
+}
+
+// drawDropChevron draws the drop-down chevron of Select-like controls with
+// its trailing edge at x (its leading edge in RTL), vertically centred on
+// cy. The theme IconChevronDown icon is used when registered.
+func drawDropChevron(theme *uikit.Theme, dst *ebiten.Image, x, cy int, rtl bool) {
+	if icon := theme.Icon(uikit.IconChevronDown); icon != nil {
+		size := theme.IconSize
+		ix := x - size
+		if rtl {
+			ix = x
+		}
+		icon.Draw(dst, image.Rect(ix, cy-size/2, ix+size, cy-size/2+size), theme.TextColor)
+		return
+	}
+
+	t := theme.Text()
+	t.SetColor(theme.TextColor)
+	if rtl {
+		t.SetAlign(etxt.Left | etxt.VertCenter)
+	} else {
+		t.SetAlign(etxt.Right | etxt.VertCenter)
+	}
+	t.Draw(dst, "▾", x, cy)
+}
+
+var gradientSrc *ebiten.Image
+
+// drawGradient fills r with a linear gradient from c0 to c1, top to bottom
+// when vertical, else left to right. Colours are premultiplied.
+func drawGradient(dst *ebiten.Image, r image.Rectangle, c0, c1 color.RGBA, vertical bool) {
+	if r.Empty() {
+		return
+	}
+	if gradientSrc == nil {
+		img := ebiten.NewImage(3, 3)
+		img.Fill(color.White)
+		gradientSrc = img.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+	}
+
+	x0, y0, x1, y1 := float32(r.Min.X), float32(r.Min.Y), float32(r.Max.X), float32(r.Max.Y)
+	vert := func(x, y float32, c color.RGBA) ebiten.Vertex {
+		return ebiten.Vertex{
+			DstX: x, DstY: y, SrcX: 1, SrcY: 1,
+			ColorR: float32(c.R) / 255, ColorG: float32(c.G) / 255,
+			ColorB: float32(c.B) / 255, ColorA: float32(c.A) / 255,
+		}
+	}
+
+	// Corners in the order top-left, top-right, bottom-left, bottom-right.
+	cs := [4]color.RGBA{c0, c1, c0, c1}
+	if vertical {
+		cs = [4]color.RGBA{c0, c0, c1, c1}
+	}
+	vs := []ebiten.Vertex{vert(x0, y0, cs[0]), vert(x1, y0, cs[1]), vert(x0, y1, cs[2]), vert(x1, y1, cs[3])}
+	op := &ebiten.DrawTrianglesOptions{ColorScaleMode: ebiten.ColorScaleModePremultipliedAlpha}
+	dst.DrawTriangles(vs, []uint16{0, 1, 2, 1, 3, 2}, gradientSrc, op)
+}
+
+// drawChecker fills r with the light and dark squares shown behind
+// translucent colours.
+func drawChecker(dst *ebiten.Image, r image.Rectangle, cell int) {
+	if r.Empty() || cell <= 0 {
+		return
+	}
+
+	sub := dst.SubImage(r).(*ebiten.Image)
+	vector.DrawFilledRect(sub, float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy()), color.RGBA{204, 204, 204, 255}, false)
+	for y := r.Min.Y; y < r.Max.Y; y += cell {
+		odd := (y-r.Min.Y)/cell%2 == 1
+		for x := r.Min.X; x < r.Max.X; x += cell {
+			if odd {
+				vector.DrawFilledRect(sub, float32(x), float32(y), float32(cell), float32(cell), color.RGBA{153, 153, 153, 255}, false)
+			}
+			odd = !odd
+		}
+	}
+}
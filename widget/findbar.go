@@ -0,0 +1,624 @@
+package widget
+
+import (
+	"fmt"
+	"image"
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/erparts/go-uikit"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/tinne26/etxt"
+)
+
+// findBar is the incremental search and replace panel of a TextArea. It is
+// drawn as an overlay on the top corner of the text and owns two inner
+// TextInputs that receive the keyboard while the panel has it.
+type findBar struct {
+	open     bool
+	replace  bool // replace row visible
+	keyboard bool // the panel (not the text) receives the keyboard
+	inRepl   bool // keyboard goes to the replace field
+
+	query *TextInput
+	repl  *TextInput
+
+	caseSensitive bool
+	regex         bool
+
+	re      *regexp.Regexp
+	invalid bool
+	partial bool      // re finds the same matches in whole lines as in the text
+	matches [][]int   // submatch indexes of every non-empty match, by offset
+	current int       // index into matches, -1 if none
+	dirty   bool      // matches predate the last edit or query change
+	staleAt time.Time // first frame that saw them stale, zero if none yet
+	origin  int       // offset incremental search starts from
+}
+
+// findLayout holds the panel rectangles, in screen coordinates.
+type findLayout struct {
+	panel                  image.Rectangle
+	query, count, cs, rx   image.Rectangle
+	repl, replOne, replAll image.Rectangle
+}
+
+// findSettle is how long the text and the query must stay unchanged before
+// the whole text is searched again. Until then only the visible lines are.
+const findSettle = 250 * time.Millisecond
+
+// findChunk is the size of the runs of whole lines matchFrom searches at a
+// time.
+const findChunk = 64 << 10
+
+const (
+	findCaseLabel   = "Aa"
+	findRegexLabel  = ".*"
+	findReplLabel   = "Replace"
+	findReplAllText = "All"
+)
+
+func newFindBar(theme *uikit.Theme) *findBar {
+	return &findBar{
+		query:   NewTextInput(theme, "Find"),
+		repl:    NewTextInput(theme, "Replace"),
+		current: -1,
+	}
+}
+
+// layout places the panel on the top trailing corner of r.
+func (f *findBar) layout(theme *uikit.Theme, r image.Rectangle, rtl bool) findLayout {
+	t := theme.Text()
+	h := theme.ControlH
+	gap := theme.SpaceS
+	cell := func(s string) int { return max(t.Measure(s).IntWidth()+theme.PadX*2, h) }
+
+	rows := 1
+	if f.replace {
+		rows = 2
+	}
+
+	w := min(r.Dx()-gap*2, h*10)
+	x := r.Max.X - gap - w
+	if rtl {
+		x = r.Min.X + gap
+	}
+	panel := image.Rect(x, r.Min.Y+gap, x+w, r.Min.Y+gap+rows*(h+gap)+gap)
+
+	var l findLayout
+	l.panel = panel
+
+	y := panel.Min.Y + gap
+	right := panel.Max.X - gap
+	l.rx = image.Rect(right-h, y, right, y+h)
+	l.cs = image.Rect(l.rx.Min.X-h, y, l.rx.Min.X, y+h)
+	l.count = image.Rect(l.cs.Min.X-cell("000/000"), y, l.cs.Min.X, y+h)
+	l.query = image.Rect(panel.Min.X+gap, y, l.count.Min.X-gap, y+h)
+
+	if f.replace {
+		y += h + gap
+		l.replAll = image.Rect(right-cell(findReplAllText), y, right, y+h)
+		l.replOne = image.Rect(l.replAll.Min.X-cell(findReplLabel), y, l.replAll.Min.X, y+h)
+		l.repl = image.Rect(panel.Min.X+gap, y, l.replOne.Min.X-gap, y+h)
+	}
+
+	return l
+}
+
+// compile rebuilds the pattern from the query and the options. Patterns are
+// multi-line: ^ and $ match at line boundaries.
+func (f *findBar) compile() {
+	f.re, f.invalid, f.partial = nil, false, false
+
+	q := f.query.Text()
+	if q == "" {
+		return
+	}
+
+	pattern := q
+	if !f.regex {
+		pattern = regexp.QuoteMeta(q)
+	}
+	if !f.caseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	pattern = "(?m)" + pattern
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		f.invalid = true
+		return
+	}
+	f.re = re
+
+	if p, err := syntax.Parse(pattern, syntax.Perl); err == nil {
+		f.partial = lineLocal(p)
+	}
+}
+
+// lineLocal reports whether re only matches within a line and doesn't depend
+// on where the text starts or ends (\A, \z). Searching runs of whole lines on
+// their own then finds the same matches as searching the full text.
+func lineLocal(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpBeginText, syntax.OpEndText, syntax.OpAnyChar:
+		return false
+	case syntax.OpLiteral:
+		if slices.Contains(re.Rune, '\n') {
+			return false
+		}
+	case syntax.OpCharClass:
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			if re.Rune[i] <= '\n' && '\n' <= re.Rune[i+1] {
+				return false
+			}
+		}
+	}
+
+	for _, sub := range re.Sub {
+		if !lineLocal(sub) {
+			return false
+		}
+	}
+	return true
+}
+
+// search finds every match in text, skipping empty ones.
+func (f *findBar) search(text string) {
+	f.matches = f.searchRange(f.matches[:0], text, 0)
+	f.dirty = false
+}
+
+// searchRange appends the non-empty matches of text to ms, shifted by off.
+func (f *findBar) searchRange(ms [][]int, text string, off int) [][]int {
+	if f.re == nil {
+		return ms
+	}
+
+	for _, m := range f.re.FindAllStringSubmatchIndex(text, -1) {
+		if m[1] <= m[0] {
+			continue
+		}
+		for k := range m {
+			if m[k] >= 0 {
+				m[k] += off
+			}
+		}
+		ms = append(ms, m)
+	}
+	return ms
+}
+
+// invalidate marks the matches stale. The full search waits for findSettle.
+func (f *findBar) invalidate() {
+	f.dirty = true
+	f.staleAt = time.Time{}
+}
+
+// indexFrom returns the first match starting at or after offset off,
+// wrapping around to the first one.
+func (f *findBar) indexFrom(off int) int {
+	if len(f.matches) == 0 {
+		return -1
+	}
+
+	i := sort.Search(len(f.matches), func(i int) bool { return f.matches[i][0] >= off })
+	if i == len(f.matches) {
+		return 0
+	}
+	return i
+}
+
+// matchesIn returns the matches of ms, sorted by offset, intersecting
+// [from, to].
+func matchesIn(ms [][]int, from, to int) [][]int {
+	i := sort.Search(len(ms), func(i int) bool { return ms[i][1] > from })
+	j := i
+	for j < len(ms) && ms[j][0] <= to {
+		j++
+	}
+	return ms[i:j]
+}
+
+// replacement returns the text replacing match m of text.
+func (f *findBar) replacement(text string, m []int) string {
+	if !f.regex {
+		return f.repl.Text()
+	}
+	return string(f.re.ExpandString(nil, f.repl.Text(), text, m))
+}
+
+// OpenFind shows the search panel and gives it the keyboard. When replace is
+// true the replace row is shown as well. A single-line selection is used as
+// the initial query.
+func (w *TextArea) OpenFind(replace bool) {
+	f := w.find
+	f.open = true
+	f.replace = f.replace || replace
+	f.keyboard = true
+	f.inRepl = false
+
+	a, b := w.Selection()
+	if sel := w.buf.Slice(a, b); sel != "" && w.buf.LineOf(a) == w.buf.LineOf(b) {
+		f.query.SetTextSilently(sel)
+	}
+	f.query.SelectAll()
+
+	f.origin = a
+	w.refreshFind()
+}
+
+// CloseFind hides the search panel and returns the keyboard to the text.
+func (w *TextArea) CloseFind() {
+	w.find.open = false
+	w.find.keyboard = false
+	w.find.matches = w.find.matches[:0]
+	w.find.current = -1
+}
+
+// FindOpen reports whether the search panel is shown.
+func (w *TextArea) FindOpen() bool { return w.find.open }
+
+// SetFindQuery sets the search text and selects the first match after the
+// caret.
+func (w *TextArea) SetFindQuery(q string) {
+	w.find.origin = w.caret
+	w.find.query.SetText(q)
+}
+
+// SetReplaceText sets the replacement text. In regex mode it may reference
+// groups with $1 or ${name}.
+func (w *TextArea) SetReplaceText(s string) {
+	w.find.repl.SetText(s)
+}
+
+// SetFindOptions sets the search modes.
+func (w *TextArea) SetFindOptions(caseSensitive, regex bool) {
+	w.find.caseSensitive = caseSensitive
+	w.find.regex = regex
+	w.refreshFind()
+}
+
+// FindMatches returns the number of matches and the index of the current one
+// (-1 if none).
+func (w *TextArea) FindMatches() (count, current int) {
+	w.updateMatches()
+	return len(w.find.matches), w.find.current
+}
+
+// FindNext selects the next match and scrolls it into view.
+func (w *TextArea) FindNext() { w.jumpMatch(1) }
+
+// FindPrevious selects the previous match and scrolls it into view.
+func (w *TextArea) FindPrevious() { w.jumpMatch(-1) }
+
+// ReplaceCurrent replaces the selected match and moves to the next one.
+// It is recorded as one undo step.
+func (w *TextArea) ReplaceCurrent() bool {
+	w.updateMatches()
+	f := w.find
+	if f.current < 0 {
+		return false
+	}
+
+	m := f.matches[f.current]
+	if a, b := w.Selection(); a != m[0] || b != m[1] {
+		// Select the match first so the user sees what gets replaced.
+		w.selectMatch(f.current)
+		return false
+	}
+
+	s := f.replacement(w.buf.String(), m)
+	w.editGroup(func() {
+		w.deleteRange(m[0], m[1])
+		s = w.insertAt(m[0], s)
+		w.caret, w.anchor = m[0]+len(s), m[0]+len(s)
+	})
+
+	w.updateMatches()
+	if i := f.indexFrom(w.caret); i >= 0 {
+		w.selectMatch(i)
+	}
+	return true
+}
+
+// ReplaceAll replaces every match as a single undo step and returns the
+// number of replacements.
+func (w *TextArea) ReplaceAll() int {
+	w.updateMatches()
+	f := w.find
+	if len(f.matches) == 0 {
+		return 0
+	}
+
+	text := w.buf.String()
+	repl := make([]string, len(f.matches))
+	for i, m := range f.matches {
+		repl[i] = f.replacement(text, m)
+	}
+
+	matches := f.matches
+	w.editGroup(func() {
+		// Back to front, so earlier offsets stay valid.
+		for i := len(matches) - 1; i >= 0; i-- {
+			w.deleteRange(matches[i][0], matches[i][1])
+			w.insertAt(matches[i][0], repl[i])
+		}
+		w.caret, w.anchor = w.snap(w.caret), w.snap(w.caret)
+	})
+
+	w.updateMatches()
+	return len(matches)
+}
+
+// refreshFind recompiles the pattern and restarts the incremental search
+// from the search origin. Only the first match from there is looked for; the
+// full list is rebuilt once the query settles. Patterns that can span lines
+// are searched in full right away.
+func (w *TextArea) refreshFind() {
+	f := w.find
+	f.compile()
+	f.invalidate()
+	f.current = -1
+
+	if !f.partial {
+		f.search(w.buf.String())
+		if i := f.indexFrom(f.origin); i >= 0 {
+			w.selectMatch(i)
+		}
+		return
+	}
+
+	if m := w.matchFrom(f.origin); m != nil {
+		w.SetSelection(m[0], m[1])
+		w.revealCaret()
+	}
+}
+
+// matchFrom returns the first non-empty match starting at or after offset
+// off, wrapping around to the start of the text, or nil. The text is searched
+// from the line of off, in chunks of whole lines, up to the first match.
+func (w *TextArea) matchFrom(off int) []int {
+	start := w.buf.LineStart(w.buf.LineOf(off))
+	if m := w.matchIn(start, w.buf.Len(), off); m != nil {
+		return m
+	}
+	return w.matchIn(0, max(start-1, 0), 0) // up to the newline before start
+}
+
+// matchIn returns the first non-empty match in [from, to) starting at or after
+// offset after. from must be a line start and to a line end.
+func (w *TextArea) matchIn(from, to, after int) []int {
+	f := w.find
+	for from < to {
+		end := to
+		if from+findChunk < to {
+			end = min(w.buf.LineEnd(w.buf.LineOf(from+findChunk)), to)
+		}
+
+		for _, m := range f.searchRange(nil, w.buf.Slice(from, end), from) {
+			if m[0] >= after {
+				return m
+			}
+		}
+		from = end + 1
+	}
+	return nil
+}
+
+// visibleMatches returns the matches intersecting [from, to], which must be
+// line boundaries. While the full list is stale only that range is searched,
+// so highlights follow every keystroke without scanning the whole text.
+func (w *TextArea) visibleMatches(from, to int) [][]int {
+	f := w.find
+	if !f.open {
+		return nil
+	}
+	if f.dirty && !f.partial {
+		w.updateMatches()
+	}
+	if f.dirty {
+		return f.searchRange(nil, w.buf.Slice(from, to), from)
+	}
+	return matchesIn(f.matches, from, to)
+}
+
+// settleMatches runs the deferred full search once the text and the query
+// stayed unchanged for findSettle.
+func (w *TextArea) settleMatches(now time.Time) {
+	f := w.find
+	if !f.dirty {
+		return
+	}
+	if f.staleAt.IsZero() {
+		f.staleAt = now
+	}
+	if now.Sub(f.staleAt) >= findSettle {
+		w.updateMatches()
+	}
+}
+
+// updateMatches searches again after the text was edited, keeping the match
+// at or after the selection start current without moving the selection.
+func (w *TextArea) updateMatches() {
+	f := w.find
+	if !f.dirty {
+		return
+	}
+
+	f.search(w.buf.String())
+	a, _ := w.Selection()
+	f.current = f.indexFrom(a)
+}
+
+func (w *TextArea) selectMatch(i int) {
+	f := w.find
+	f.current = i
+	m := f.matches[i]
+	w.SetSelection(m[0], m[1])
+	w.revealCaret()
+}
+
+func (w *TextArea) jumpMatch(dir int) {
+	w.updateMatches()
+	f := w.find
+	n := len(f.matches)
+	if n == 0 {
+		return
+	}
+
+	i := f.current
+	switch {
+	case i < 0 && dir > 0:
+		i = f.indexFrom(w.caret)
+	case i < 0:
+		i = (f.indexFrom(w.caret) - 1 + n) % n
+	default:
+		i = (i + dir + n) % n
+	}
+	w.selectMatch(i)
+	f.origin = f.matches[i][0]
+}
+
+func (w *TextArea) onFindQueryChange(e uikit.Event) bool {
+	w.refreshFind()
+	return false
+}
+
+// updateFind runs the panel for one frame. It returns true when the panel
+// consumed the keyboard this frame.
+func (w *TextArea) updateFind(ctx *uikit.Context, rtl bool) bool {
+	f := w.find
+	theme := ctx.Theme()
+	l := f.layout(theme, w.Measure(false), rtl)
+
+	f.query.SetFrame(l.query.Min.X, l.query.Min.Y, l.query.Dx())
+	f.repl.SetFrame(l.repl.Min.X, l.repl.Min.Y, l.repl.Dx())
+	for _, in := range []*TextInput{f.query, f.repl} {
+		in.SetEnabled(w.IsEnabled())
+		in.SetDirection(w.Direction())
+	}
+
+	ptr := ctx.Pointer()
+	if ptr.IsJustDown && ptr.Position.In(l.panel) {
+		f.keyboard = true
+
+		switch p := ptr.Position; {
+		case p.In(l.query):
+			f.inRepl = false
+		case f.replace && p.In(l.repl):
+			f.inRepl = true
+		case p.In(l.cs):
+			w.SetFindOptions(!f.caseSensitive, f.regex)
+		case p.In(l.rx):
+			w.SetFindOptions(f.caseSensitive, !f.regex)
+		case f.replace && p.In(l.replOne):
+			w.ReplaceCurrent()
+		case f.replace && p.In(l.replAll):
+			w.ReplaceAll()
+		}
+	}
+
+	focused := w.IsFocused() && f.keyboard
+	f.query.SetFocused(focused && !f.inRepl)
+	f.repl.SetFocused(focused && f.inRepl)
+	f.query.SetHovered(ptr.Position.In(l.query))
+	f.repl.SetHovered(f.replace && ptr.Position.In(l.repl))
+
+	if !focused {
+		w.settleMatches(ctx.Now())
+		return false
+	}
+
+	enter := inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyKPEnter)
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		w.CloseFind()
+	case enter && f.inRepl && shortcutPressed():
+		w.ReplaceAll()
+	case enter && f.inRepl:
+		w.ReplaceCurrent()
+	case enter && shiftPressed():
+		w.FindPrevious()
+	case enter:
+		w.FindNext()
+	default:
+		// The inputs blur themselves on Enter, so they only run on other frames.
+		if f.inRepl {
+			f.repl.Update(ctx)
+		} else {
+			f.query.Update(ctx)
+		}
+	}
+
+	w.settleMatches(ctx.Now())
+	return true
+}
+
+func (w *TextArea) HitTest(ctx *uikit.Context, pos image.Point) bool {
+	r := w.Measure(false)
+	if pos.In(r) {
+		return true
+	}
+	return w.find.open && pos.In(w.find.layout(ctx.Theme(), r, w.IsRTL(ctx)).panel)
+}
+
+func (w *TextArea) OverlayActive() bool { return w.find.open }
+
+// DrawOverlay draws the search panel.
+func (w *TextArea) DrawOverlay(ctx *uikit.Context, dst *ebiten.Image) {
+	f := w.find
+	if !f.open {
+		return
+	}
+
+	theme := ctx.Theme()
+	l := f.layout(theme, w.Measure(false), w.IsRTL(ctx))
+
+	w.DrawRoundedRect(dst, l.panel, theme.Radius, theme.SurfaceColor)
+	w.DrawRoundedBorder(dst, l.panel, theme.Radius, theme.BorderW, theme.BorderColor)
+
+	f.query.Draw(ctx, dst)
+
+	t := theme.Text()
+	t.SetAlign(etxt.Center)
+
+	count, col := "", theme.MutedTextColor
+	switch {
+	case f.invalid:
+		count, col = "!", theme.ErrorTextColor
+	case f.dirty && f.re != nil:
+		count = "..."
+	case f.query.Text() != "" && len(f.matches) == 0:
+		count = "0/0"
+	case len(f.matches) > 0:
+		count = fmt.Sprintf("%d/%d", f.current+1, len(f.matches))
+	}
+	t.SetColor(col)
+	t.Draw(dst, count, l.count.Min.X+l.count.Dx()/2, l.count.Min.Y+l.count.Dy()/2)
+
+	ptr := ctx.Pointer()
+	cell := func(r image.Rectangle, label string, active bool) {
+		switch {
+		case active:
+			w.DrawRoundedRect(dst, r, theme.Radius, theme.FocusColor)
+		case ptr.Position.In(r):
+			w.DrawRoundedRect(dst, r, theme.Radius, theme.SurfaceHoverColor)
+		}
+		t.SetColor(theme.TextColor)
+		t.Draw(dst, label, r.Min.X+r.Dx()/2, r.Min.Y+r.Dy()/2)
+	}
+
+	cell(l.cs, findCaseLabel, f.caseSensitive)
+	cell(l.rx, findRegexLabel, f.regex)
+
+	if f.replace {
+		f.repl.Draw(ctx, dst)
+		cell(l.replOne, findReplLabel, false)
+		cell(l.replAll, findReplAllText, false)
+	}
+}
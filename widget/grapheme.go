@@ -0,0 +1,134 @@
+package widget
+
+import (
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// Text editing works on grapheme clusters (UAX #29), so emoji ZWJ sequences,
+// flags, skin tones and combining marks are deleted, measured and masked as a
+// single user-perceived character. Offsets are byte offsets into the string
+// and are always kept on cluster boundaries.
+
+// nextGrapheme returns the boundary following offset i.
+func nextGrapheme(s string, i int) int {
+	if i >= len(s) {
+		return len(s)
+	}
+
+	cluster, _, _, _ := uniseg.FirstGraphemeClusterInString(s[i:], -1)
+	return i + len(cluster)
+}
+
+// prevGrapheme returns the boundary preceding offset i.
+func prevGrapheme(s string, i int) int {
+	if i <= 0 {
+		return 0
+	}
+
+	prev, pos, state := 0, 0, -1
+	rest := s
+	for len(rest) > 0 && pos < i {
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		prev = pos
+		pos += len(cluster)
+	}
+
+	return prev
+}
+
+// graphemeCount returns the number of user-perceived characters in s.
+func graphemeCount(s string) int {
+	return uniseg.GraphemeClusterCount(s)
+}
+
+// truncateGraphemes returns the first n grapheme clusters of s.
+func truncateGraphemes(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+
+	pos, state := 0, -1
+	rest := s
+	for ; n > 0 && len(rest) > 0; n-- {
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		pos += len(cluster)
+	}
+
+	return s[:pos]
+}
+
+// maskGraphemes replaces every grapheme cluster of s with mask.
+func maskGraphemes(s string, mask rune) string {
+	return strings.Repeat(string(mask), graphemeCount(s))
+}
+
+// graphemeAtX returns the cluster boundary of s closest to the horizontal
+// offset x, using the widths reported by measure.
+func graphemeAtX(s string, x int, measure func(string) int) int {
+	if x <= 0 {
+		return 0
+	}
+
+	pos, state := 0, -1
+	rest := s
+	for len(rest) > 0 {
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		next := pos + len(cluster)
+
+		left, right := measure(s[:pos]), measure(s[:next])
+		if x < right {
+			if x-left < right-x {
+				return pos
+			}
+			return next
+		}
+		pos = next
+	}
+
+	return len(s)
+}
+
+// fitGraphemes trims s so that inserting it into a text of count clusters
+// keeps the result within maxLen clusters. A maxLen <= 0 means unlimited.
+// before is the text preceding the insertion point (its last cluster is
+// enough): clusters of s that join it, like a combining mark, a skin-tone
+// modifier or a ZWJ continuation, don't add to the count.
+func fitGraphemes(count int, before, s string, maxLen int) string {
+	if maxLen <= 0 || s == "" {
+		return s
+	}
+
+	joined := before + s
+	room := maxLen - count + graphemeCount(before)
+
+	pos, n, state := 0, 0, -1
+	rest := joined
+	for len(rest) > 0 {
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		if n++; n > room {
+			break
+		}
+		pos += len(cluster)
+	}
+
+	if pos <= len(before) {
+		return ""
+	}
+	return joined[len(before):pos]
+}
+
+// snapGrapheme clamps i into s and moves it back to the closest preceding
+// cluster boundary.
+func snapGrapheme(s string, i int) int {
+	i = clampInt(i, 0, len(s))
+	if i < len(s) && nextGrapheme(s, prevGrapheme(s, i)) != i {
+		return prevGrapheme(s, i)
+	}
+	return i
+}
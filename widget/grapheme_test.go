@@ -0,0 +1,168 @@
+package widget
+
+import "testing"
+
+const (
+	family   = "\U0001F468\u200D\U0001F469\u200D\U0001F467\u200D\U0001F466" // ZWJ sequence
+	flagES   = "\U0001F1EA\U0001F1F8"                                       // regional indicator pair
+	thumbs   = "\U0001F44D\U0001F3FD"                                       // skin-tone modifier
+	eAcute   = "e\u0301"                                                    // combining accent
+	mixedStr = "a" + family + flagES + thumbs + eAcute + "b"
+)
+
+func TestNextGrapheme(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		i    int
+		want int
+	}{
+		{"ascii", "abc", 0, 1},
+		{"end", "abc", 3, 3},
+		{"past end", "abc", 9, 3},
+		{"zwj family", family + "x", 0, len(family)},
+		{"flag", flagES + flagES, 0, len(flagES)},
+		{"second flag", flagES + flagES, len(flagES), 2 * len(flagES)},
+		{"skin tone", thumbs + "x", 0, len(thumbs)},
+		{"combining accent", eAcute + "x", 0, len(eAcute)},
+		{"mixed", mixedStr, 1, 1 + len(family)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextGrapheme(tt.s, tt.i); got != tt.want {
+				t.Errorf("nextGrapheme(%q, %d) = %d, want %d", tt.s, tt.i, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrevGrapheme(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		i    int
+		want int
+	}{
+		{"ascii", "abc", 2, 1},
+		{"start", "abc", 0, 0},
+		{"zwj family", "x" + family, 1 + len(family), 1},
+		{"flag", flagES + flagES, 2 * len(flagES), len(flagES)},
+		{"skin tone", "x" + thumbs, 1 + len(thumbs), 1},
+		{"combining accent", "x" + eAcute, 1 + len(eAcute), 1},
+		{"mixed end", mixedStr, len(mixedStr), len(mixedStr) - 1},
+		{"mixed accent", mixedStr, len(mixedStr) - 1, len(mixedStr) - 1 - len(eAcute)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := prevGrapheme(tt.s, tt.i); got != tt.want {
+				t.Errorf("prevGrapheme(%q, %d) = %d, want %d", tt.s, tt.i, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateGraphemes(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		n    int
+		want string
+	}{
+		{"zero", "abc", 0, ""},
+		{"negative", "abc", -1, ""},
+		{"ascii", "abc", 2, "ab"},
+		{"longer than text", "abc", 5, "abc"},
+		{"zwj family", family + family, 1, family},
+		{"flags", flagES + flagES, 1, flagES},
+		{"skin tone", thumbs + "x", 1, thumbs},
+		{"combining accent", eAcute + eAcute, 1, eAcute},
+		{"mixed", mixedStr, 3, "a" + family + flagES},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateGraphemes(tt.s, tt.n); got != tt.want {
+				t.Errorf("truncateGraphemes(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFitGraphemes(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		at     int
+		s      string
+		maxLen int
+		want   string
+	}{
+		{"unlimited", "abc", 3, "def", 0, "def"},
+		{"fits", "ab", 2, "cd", 4, "cd"},
+		{"trimmed", "ab", 2, "cde", 4, "cd"},
+		{"full", "abcd", 4, "e", 4, ""},
+		{"over limit", "abcde", 5, "f", 4, ""},
+		{"zwj family", "a", 1, family + family, 2, family},
+		{"flags", "", 0, flagES + flagES + flagES, 2, flagES + flagES},
+		{"skin tone", "ab", 2, thumbs + thumbs, 3, thumbs},
+		{"combining accent", eAcute, len(eAcute), eAcute + eAcute, 2, eAcute},
+		{"combining accent in full field", "abce", 4, "\u0301", 4, "\u0301"},
+		{"combining accent mid text in full field", "abce", 3, "\u0301", 4, "\u0301"},
+		{"skin tone in full field", "ab\U0001F44D", len("ab\U0001F44D"), "\U0001F3FD", 3, "\U0001F3FD"},
+		{"zwj continuation in full field", "\U0001F468", len("\U0001F468"), "\u200D\U0001F469", 1, "\u200D\U0001F469"},
+		{"joining mark then new cluster", "abce", 4, "\u0301f", 4, "\u0301"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := tt.text[:tt.at]
+			got := fitGraphemes(graphemeCount(tt.text), before[prevGrapheme(before, tt.at):], tt.s, tt.maxLen)
+			if got != tt.want {
+				t.Errorf("fitGraphemes(%q at %d, %q, %d) = %q, want %q", tt.text, tt.at, tt.s, tt.maxLen, got, tt.want)
+			}
+			if tt.maxLen > 0 && graphemeCount(tt.text) <= tt.maxLen {
+				if n := graphemeCount(tt.text[:tt.at] + got + tt.text[tt.at:]); n > tt.maxLen {
+					t.Errorf("result has %d clusters, over %d", n, tt.maxLen)
+				}
+			}
+		})
+	}
+}
+
+func TestPasswordMask(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"empty", "", ""},
+		{"ascii", "abc", "•••"},
+		{"zwj family", family, "•"},
+		{"flags", flagES + flagES, "••"},
+		{"skin tone", thumbs, "•"},
+		{"combining accent", eAcute, "•"},
+		{"mixed", mixedStr, "••••••"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &TextInput{text: tt.text, password: true, mask: '•'}
+			if got := w.display(); got != tt.want {
+				t.Errorf("display() = %q, want %q", got, tt.want)
+			}
+
+			// Every cluster boundary maps to a mask boundary and back.
+			for off := 0; off < len(tt.text); off = nextGrapheme(tt.text, off) {
+				d := w.toDisplay(off)
+				if back := w.displayToText(d); back != off {
+					t.Errorf("displayToText(toDisplay(%d)) = %d", off, back)
+				}
+			}
+			if d := w.toDisplay(len(tt.text)); d != len(tt.want) {
+				t.Errorf("toDisplay(end) = %d, want %d", d, len(tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package widget
+
+import (
+	"image"
+	"strconv"
+	"strings"
+
+	"github.com/erparts/go-uikit"
+	"github.com/erparts/go-uikit/common"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/tinne26/etxt"
+)
+
+// MarkerKind selects how a gutter marker is drawn.
+type MarkerKind int
+
+const (
+	MarkerInfo MarkerKind = iota
+	MarkerWarning
+	MarkerError
+)
+
+// GutterMarker decorates a line in the TextArea gutter (e.g. a compiler
+// error). Markers follow their line when lines are inserted or removed above.
+type GutterMarker struct {
+	Kind    MarkerKind
+	Message string
+}
+
+// SetMarker sets the marker of a line (0-based).
+func (w *TextArea) SetMarker(line int, m GutterMarker) {
+	if w.markers == nil {
+		w.markers = map[int]GutterMarker{}
+	}
+	w.markers[line] = m
+}
+
+// Marker returns the marker of a line, if any.
+func (w *TextArea) Marker(line int) (GutterMarker, bool) {
+	m, ok := w.markers[line]
+	return m, ok
+}
+
+// ClearMarker removes the marker of a line.
+func (w *TextArea) ClearMarker(line int) {
+	delete(w.markers, line)
+}
+
+// ClearMarkers removes every marker.
+func (w *TextArea) ClearMarkers() {
+	clear(w.markers)
+}
+
+// shiftMarkers keeps markers on their lines after an edit starting on line
+// removed and inserted the given number of line breaks. Markers of lines
+// joined into line are dropped. whole tells the edit began at the start of
+// line and removed whole lines, if any: line then moves with the lines after
+// it, and the markers of the removed lines are dropped.
+func (w *TextArea) shiftMarkers(line int, whole bool, removed, inserted int) {
+	if len(w.markers) == 0 || removed == inserted {
+		return
+	}
+
+	moved := make(map[int]GutterMarker, len(w.markers))
+	for l, m := range w.markers {
+		switch {
+		case l < line, l == line && !whole:
+			moved[l] = m
+		case l >= line+removed && whole, l > line+removed:
+			moved[l-removed+inserted] = m
+		}
+	}
+	w.markers = moved
+}
+
+// wholeLines reports whether an edit at offset at removing removed starts at
+// a line start and removes whole lines, if any (see shiftMarkers).
+func (w *TextArea) wholeLines(at int, removed string) bool {
+	return at == w.buf.LineStart(w.buf.LineOf(at)) && (removed == "" || strings.HasSuffix(removed, "\n"))
+}
+
+// gutterWidth fits the marker column and the widest line number.
+func (w *TextArea) gutterWidth(theme *uikit.Theme) int {
+	t := theme.Text()
+	digits := max(len(strconv.Itoa(w.buf.LineCount())), 2)
+	return t.Measure(" ").IntHeight() + t.Measure(strings.Repeat("0", digits)).IntWidth()
+}
+
+// areas splits r into the text area and the gutter, which sits on the
+// leading side.
+func (w *TextArea) areas(theme *uikit.Theme, r image.Rectangle, rtl bool) (content, gutter image.Rectangle) {
+	content = common.Inset(r, theme.PadX, theme.PadY)
+	if !w.ShowGutter {
+		return content, image.Rectangle{}
+	}
+
+	gw := w.gutterWidth(theme)
+	gutter = content
+	if rtl {
+		gutter.Min.X = content.Max.X - gw
+		content.Max.X = gutter.Min.X - theme.PadX
+	} else {
+		gutter.Max.X = content.Min.X + gw
+		content.Min.X = gutter.Max.X + theme.PadX
+	}
+
+	return content, gutter
+}
+
+// selectLines selects whole lines from the anchor line to line, line break
+// included, as done by a click (or Shift+click) on the gutter.
+func (w *TextArea) selectLines(line int, extend bool) {
+	next := func(l int) int {
+		if l+1 < w.buf.LineCount() {
+			return w.buf.LineStart(l + 1)
+		}
+		return w.buf.Len()
+	}
+
+	from := line
+	if extend {
+		from = w.buf.LineOf(w.anchor)
+	}
+
+	if line < from {
+		w.SetSelection(next(from), w.buf.LineStart(line))
+	} else {
+		w.SetSelection(w.buf.LineStart(from), next(line))
+	}
+}
+
+// drawGutter draws line numbers and markers for lines first..last, in sync
+// with the vertical scroll.
+func (w *TextArea) drawGutter(ctx *uikit.Context, dst *ebiten.Image, gutter image.Rectangle, first, last, lineH int, rtl bool) {
+	theme := ctx.Theme()
+
+	sepX := gutter.Max.X + theme.PadX/2
+	if rtl {
+		sepX = gutter.Min.X - theme.PadX/2
+	}
+	w.DrawRoundedRect(dst, image.Rect(sepX, gutter.Min.Y, sepX+max(theme.BorderW, 1), gutter.Max.Y), 0, theme.BorderColor)
+
+	sub := dst.SubImage(gutter).(*ebiten.Image)
+
+	t := theme.Text()
+	t.SetAlign(etxt.Right | etxt.VertCenter)
+	numX := gutter.Max.X
+	markX := gutter.Min.X
+	if rtl {
+		t.SetAlign(etxt.Left | etxt.VertCenter)
+		numX, markX = gutter.Min.X, gutter.Max.X-lineH
+	}
+
+	caretLine := w.buf.LineOf(w.caret)
+	dot := max(lineH/3, 2)
+	for index := first; index <= last; index++ {
+		y := gutter.Min.Y + index*lineH - w.Scroll.ScrollY
+
+		if m, ok := w.markers[index]; ok {
+			col := theme.FocusColor
+			switch m.Kind {
+			case MarkerWarning:
+				col = theme.WarningColor
+			case MarkerError:
+				col = theme.ErrorBorderColor
+			}
+			cx, cy := markX+lineH/2, y+lineH/2
+			w.DrawRoundedRect(sub, image.Rect(cx-dot, cy-dot, cx+dot, cy+dot), dot, col)
+		}
+
+		t.SetColor(theme.MutedTextColor)
+		if index == caretLine {
+			t.SetColor(theme.TextColor)
+		}
+		t.Draw(sub, strconv.Itoa(index+1), numX, y+lineH/2)
+	}
+}
@@ -0,0 +1,102 @@
+package widget
+
+import (
+	"image/color"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+// TextStyle describes how a span of text is drawn.
+// Zero values fall back to the widget defaults (theme font and text colour,
+// no underline, no background).
+type TextStyle struct {
+	Color      color.RGBA
+	Font       *sfnt.Font // e.g. a bold or italic face of the theme font
+	Underline  bool
+	Background color.RGBA
+}
+
+// Span styles the [Start, End) byte range of a line.
+type Span struct {
+	Start, End int
+	Style      TextStyle
+}
+
+// Highlighter returns the styled spans of a single line. Results are cached
+// per line and only requested again when the line changes.
+type Highlighter interface {
+	HighlightLine(index int, line string) []Span
+}
+
+// HighlighterFunc adapts a function to the Highlighter interface.
+type HighlighterFunc func(index int, line string) []Span
+
+func (f HighlighterFunc) HighlightLine(index int, line string) []Span {
+	return f(index, line)
+}
+
+// DocumentHighlighter highlights syntaxes where a line depends on the
+// previous ones (block comments, multi-line strings). Lines are highlighted
+// in order, each from the state the previous one ended in (nil for the first
+// line). Results are cached; an edit only drops the edited line and the ones
+// after it, which are highlighted again when they are drawn.
+type DocumentHighlighter interface {
+	HighlightNext(line string, state any) (spans []Span, next any)
+}
+
+// DocumentHighlighterFunc adapts a function to the DocumentHighlighter
+// interface.
+type DocumentHighlighterFunc func(line string, state any) ([]Span, any)
+
+func (f DocumentHighlighterFunc) HighlightNext(line string, state any) ([]Span, any) {
+	return f(line, state)
+}
+
+// docLine is the cached highlight of a line of a DocumentHighlighter, with
+// the state it ended in.
+type docLine struct {
+	spans []Span
+	state any
+}
+
+// cachedSpans holds the highlight of a line along with the text it was
+// computed for, so stale entries are detected.
+type cachedSpans struct {
+	text  string
+	spans []Span
+}
+
+// styleAt returns the style of the span covering offset i, resolved against
+// the default style.
+func styleAt(spans []Span, i int, def TextStyle) TextStyle {
+	st := def
+	for _, sp := range spans {
+		if i < sp.Start || i >= sp.End {
+			continue
+		}
+
+		if sp.Style.Color.A != 0 {
+			st.Color = sp.Style.Color
+		}
+		if sp.Style.Font != nil {
+			st.Font = sp.Style.Font
+		}
+		if sp.Style.Background.A != 0 {
+			st.Background = sp.Style.Background
+		}
+		st.Underline = st.Underline || sp.Style.Underline
+	}
+
+	return st
+}
+
+// hasFonts reports whether any span changes the font, which makes measuring
+// depend on the styles.
+func hasFonts(spans []Span) bool {
+	for _, sp := range spans {
+		if sp.Style.Font != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package widget
+
+import "strings"
+
+// textEdit replaces removed with inserted at byte offset at.
+type textEdit struct {
+	at       int
+	removed  string
+	inserted string
+}
+
+// editGroup is one undoable step: every edit made by a single user action,
+// plus the caret and selection to restore on both sides.
+type editGroup struct {
+	edits []textEdit
+
+	caretBefore, anchorBefore int
+	caretAfter, anchorAfter   int
+}
+
+// typing reports whether g is a single insertion of word characters, which
+// is merged with the previous typing step.
+func (g *editGroup) typing() bool {
+	if len(g.edits) != 1 {
+		return false
+	}
+
+	e := g.edits[0]
+	return e.removed == "" && e.inserted != "" && !strings.ContainsAny(e.inserted, " \t\n")
+}
+
+// textHistory records the edits of a text widget for undo/redo.
+type textHistory struct {
+	undo, redo []editGroup
+
+	current *editGroup
+	sealed  bool // the last step must not be merged with the next one
+}
+
+// historyLimit bounds the number of undo steps kept.
+const historyLimit = 500
+
+// begin starts collecting the edits of a user action.
+func (h *textHistory) begin(caret, anchor int) {
+	if h.current == nil {
+		h.current = &editGroup{caretBefore: caret, anchorBefore: anchor}
+	}
+}
+
+// record adds an edit to the step being collected.
+func (h *textHistory) record(e textEdit) {
+	if h.current != nil {
+		h.current.edits = append(h.current.edits, e)
+	}
+}
+
+// commit closes the step being collected. Consecutive typing is merged into
+// a single step.
+func (h *textHistory) commit(caret, anchor int) {
+	g := h.current
+	h.current = nil
+	if g == nil || len(g.edits) == 0 {
+		return
+	}
+
+	g.caretAfter, g.anchorAfter = caret, anchor
+	h.redo = h.redo[:0]
+
+	if n := len(h.undo); n > 0 && !h.sealed && g.typing() && h.undo[n-1].typing() {
+		last := &h.undo[n-1]
+		prev := &last.edits[0]
+		if g.edits[0].at == prev.at+len(prev.inserted) {
+			prev.inserted += g.edits[0].inserted
+			last.caretAfter, last.anchorAfter = g.caretAfter, g.anchorAfter
+			return
+		}
+	}
+
+	h.undo = append(h.undo, *g)
+	if len(h.undo) > historyLimit {
+		h.undo = h.undo[len(h.undo)-historyLimit:]
+	}
+	h.sealed = false
+}
+
+// seal prevents the next typing from merging with the last step (e.g. after
+// the caret was moved).
+func (h *textHistory) seal() { h.sealed = true }
+
+func (h *textHistory) clear() {
+	h.undo, h.redo = nil, nil
+	h.current = nil
+	h.sealed = false
+}
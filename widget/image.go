@@ -0,0 +1,168 @@
+package widget
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/erparts/go-uikit"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var _ uikit.Widget = (*Image)(nil)
+
+// ImageFit controls how an Image scales its picture into the widget.
+type ImageFit int
+
+const (
+	// ImageFitContain scales the picture to fit, letterboxing the rest.
+	ImageFitContain ImageFit = iota
+	// ImageFitCover scales the picture to cover the widget, cropping it.
+	ImageFitCover
+	// ImageFitStretch fills the widget, ignoring the aspect ratio.
+	ImageFitStretch
+	// ImageFitCenter keeps the picture at its size, centred and cropped.
+	ImageFitCenter
+)
+
+// Image shows a picture.
+// - Without SetHeight the height follows the picture aspect ratio for the
+// laid out width.
+// - Rounded clips the corners with the theme radius; a non-zero Tint
+// multiplies the picture colours.
+type Image struct {
+	uikit.Base
+
+	img *ebiten.Image
+
+	Fit     ImageFit
+	Tint    color.RGBA
+	Rounded bool
+
+	height int
+	width  int // last laid out width, for the aspect ratio
+
+	scratch *ebiten.Image
+	mask    *ebiten.Image
+	maskR   int // radius the mask was drawn with
+}
+
+// NewImage creates an image widget; img may be nil.
+func NewImage(theme *uikit.Theme, img image.Image) *Image {
+	cfg := uikit.NewWidgetBaseConfig(theme)
+	cfg.DrawSurface = false
+	cfg.DrawBorder = false
+	cfg.DrawFocus = false
+
+	w := &Image{
+		Base: uikit.NewBase(cfg),
+		img:  uikit.EbitenImage(img),
+	}
+	w.Base.HeightCalculator = w.heightCalculator
+
+	return w
+}
+
+func (w *Image) heightCalculator() int {
+	if w.height > 0 {
+		return w.height
+	}
+	if w.img == nil {
+		return 0
+	}
+
+	b := w.img.Bounds()
+	if b.Dx() == 0 {
+		return 0
+	}
+	return w.width * b.Dy() / b.Dx()
+}
+
+func (w *Image) Focusable() bool { return false }
+
+func (w *Image) SetFrame(x, y, width int) {
+	w.width = max(width, 0)
+	w.Base.SetFrame(x, y, width)
+}
+
+// SetHeight fixes the height. Use 0 to follow the aspect ratio.
+func (w *Image) SetHeight(h int) {
+	w.height = h
+}
+
+// SetImage replaces the picture; img may be nil.
+func (w *Image) SetImage(img image.Image) {
+	w.img = uikit.EbitenImage(img)
+}
+
+func (w *Image) Image() *ebiten.Image { return w.img }
+
+func (w *Image) Update(ctx *uikit.Context) {
+	r := w.Measure(false)
+	w.SetFrame(r.Min.X, r.Min.Y, r.Dx())
+}
+
+// geoM places the picture in a w×h box at the origin.
+func (w *Image) geoM(b image.Rectangle, rw, rh int) ebiten.GeoM {
+	iw, ih := float64(b.Dx()), float64(b.Dy())
+	sx, sy := float64(rw)/iw, float64(rh)/ih
+
+	switch w.Fit {
+	case ImageFitContain:
+		sx = min(sx, sy)
+		sy = sx
+	case ImageFitCover:
+		sx = max(sx, sy)
+		sy = sx
+	case ImageFitCenter:
+		sx, sy = 1, 1
+	}
+
+	var m ebiten.GeoM
+	m.Scale(sx, sy)
+	m.Translate((float64(rw)-iw*sx)/2, (float64(rh)-ih*sy)/2)
+	return m
+}
+
+func (w *Image) Draw(ctx *uikit.Context, dst *ebiten.Image) {
+	r := w.Base.Draw(ctx, dst)
+	if w.img == nil || r.Empty() || w.img.Bounds().Empty() {
+		return
+	}
+
+	op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+	op.GeoM = w.geoM(w.img.Bounds(), r.Dx(), r.Dy())
+	if w.Tint.A != 0 {
+		op.ColorScale.ScaleWithColor(w.Tint)
+	}
+	if !w.IsEnabled() {
+		op.ColorScale.ScaleAlpha(0.5)
+	}
+
+	radius := ctx.Theme().Radius
+	if !w.Rounded || radius <= 0 {
+		op.GeoM.Translate(float64(r.Min.X), float64(r.Min.Y))
+		dst.SubImage(r).(*ebiten.Image).DrawImage(w.img, op)
+		return
+	}
+
+	// Rounded: draw into a scratch image, cut the corners with a mask,
+	// then copy it over.
+	if w.scratch == nil || w.scratch.Bounds().Size() != r.Size() {
+		w.scratch = ebiten.NewImage(r.Dx(), r.Dy())
+		w.mask = ebiten.NewImage(r.Dx(), r.Dy())
+		w.maskR = 0
+	}
+	if w.maskR != radius {
+		w.mask.Clear()
+		w.DrawRoundedRect(w.mask, w.mask.Bounds(), radius, color.RGBA{255, 255, 255, 255})
+		w.maskR = radius
+	}
+
+	w.scratch.Clear()
+	w.scratch.DrawImage(w.img, op)
+	w.scratch.DrawImage(w.mask, &ebiten.DrawImageOptions{Blend: ebiten.BlendDestinationIn})
+
+	out := &ebiten.DrawImageOptions{}
+	out.GeoM.Translate(float64(r.Min.X), float64(r.Min.Y))
+	dst.DrawImage(w.scratch, out)
+}
@@ -0,0 +1,29 @@
+package widget
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// keyRepeat reports whether key was just pressed, or has been held long
+// enough to auto-repeat (caret movement, deletion).
+func keyRepeat(key ebiten.Key) bool {
+	d := inpututil.KeyPressDuration(key)
+	if d == 1 {
+		return true
+	}
+
+	delay := ebiten.TPS() / 2
+	interval := max(ebiten.TPS()/20, 1)
+	return d > delay && (d-delay)%interval == 0
+}
+
+// shortcutPressed reports whether the platform shortcut modifier (Ctrl, or
+// Cmd on macOS) is held.
+func shortcutPressed() bool {
+	return ebiten.IsKeyPressed(ebiten.KeyControl) || ebiten.IsKeyPressed(ebiten.KeyMeta)
+}
+
+func shiftPressed() bool {
+	return ebiten.IsKeyPressed(ebiten.KeyShift)
+}
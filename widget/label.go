@@ -16,6 +16,7 @@ type Label struct {
 
 	lastHeight int
 	refWidth   int
+	rtl        bool
 }
 
 func NewLabel(theme *uikit.Theme, text string) *Label {
@@ -71,26 +72,31 @@ func (w *Label) Update(ctx *uikit.Context) {
 		}
 	}
 
-	if w.refWidth != r.Dx() {
+	if w.refWidth != r.Dx() || w.rtl != w.IsRTL(ctx) {
 		w.refWidth = r.Dx()
-		renderer := w.textRenderer(ctx.Theme())
-		w.lastHeight = renderer.MeasureWithWrap(w.text, w.refWidth).IntHeight()
+		w.rtl = w.IsRTL(ctx)
+		renderer := w.textRenderer(ctx.Theme(), w.rtl)
+		w.lastHeight = wrappedHeight(renderer, w.text, w.refWidth, w.rtl)
 	}
 }
 
 func (w *Label) Draw(ctx *uikit.Context, dst *ebiten.Image) {
 	r := w.Base.Draw(ctx, dst)
 
-	renderer := w.textRenderer(ctx.Theme())
+	rtl := w.IsRTL(ctx)
+	renderer := w.textRenderer(ctx.Theme(), rtl)
 	x := renderer.GetAlign().Horz().GetHorzAnchor(r.Min.X, r.Max.X)
 
-	renderer.DrawWithWrap(dst, w.text, x, r.Min.Y+(r.Dy()/2), r.Dx())
+	drawWrapped(renderer, dst, w.text, x, r.Min.Y+(r.Dy()/2), r.Dx(), rtl)
 }
 
-func (w *Label) textRenderer(theme *uikit.Theme) *etxt.Renderer {
+func (w *Label) textRenderer(theme *uikit.Theme, rtl bool) *etxt.Renderer {
 	renderer := theme.Text()
 	renderer.SetColor(theme.TextColor)
 	renderer.SetAlign(etxt.Left | etxt.VertCenter)
+	if rtl {
+		renderer.SetAlign(etxt.Right | etxt.VertCenter)
+	}
 	for _, mod := range w.modifiers {
 		mod(theme, renderer)
 	}
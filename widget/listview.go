@@ -0,0 +1,397 @@
+package widget
+
+import (
+	"image"
+	"sort"
+
+	"github.com/erparts/go-uikit"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var _ uikit.Widget = (*ListView)(nil)
+
+// ListSource feeds a ListView. Row widgets are recycled: a row created by
+// NewRow may later be bound to any other item.
+type ListSource interface {
+	Len() int
+	NewRow() uikit.Widget
+	BindRow(row uikit.Widget, index int)
+}
+
+// ListView shows a long list of items, creating row widgets only for the
+// visible items.
+// - Rows come from a ListSource and are reused as the list scrolls.
+// - RowHeight fixes the row height; with 0 each row is measured once bound
+// and unmeasured rows count as ControlH.
+// - Rows are drawn by the list and take no input of their own: the list is
+// a single Tab stop handling selection (see SelectionMode) and keys.
+// - Up/Down, PageUp/PageDown and Home/End move the cursor (Shift extends a
+// multi selection, Ctrl moves the cursor alone), Space selects, Enter calls
+// OnActivate.
+// - With SetHeight the list scrolls and follows the cursor; otherwise rows
+// outside the screen are still skipped.
+// - EventValueChange is dispatched when the cursor or the selection
+// changes; it carries the cursor index in Event.Value.
+type ListView struct {
+	uikit.Base
+
+	source ListSource
+
+	// RowHeight is the height of every row, or 0 to measure the rows.
+	RowHeight int
+
+	// OnActivate is called when Enter is pressed on an item.
+	OnActivate func(index int)
+
+	sel    listSelection
+	height int
+	scroll uikit.Scroller
+	reveal int // index to scroll into view, -1 if none
+
+	n       int // source length at the last sync, -1 to rebind everything
+	bound   map[int]uikit.Widget
+	free    []uikit.Widget
+	heights []int // measured row heights, 0 while unknown
+	offsets []int // measured row tops (n+1 entries), nil when stale
+}
+
+func NewListView(theme *uikit.Theme, source ListSource) *ListView {
+	cfg := uikit.NewWidgetBaseConfig(theme)
+	cfg.DrawSurface = false // drawn over the visible part only
+	cfg.DrawBorder = false
+	cfg.DrawFocus = false // drawn around the cursor row instead
+
+	w := &ListView{
+		Base:      uikit.NewBase(cfg),
+		source:    source,
+		RowHeight: theme.ControlH,
+		sel:       newListSelection(),
+		scroll:    uikit.NewScroller(),
+		reveal:    -1,
+		n:         -1,
+		bound:     make(map[int]uikit.Widget),
+	}
+	w.Base.HeightCalculator = w.heightCalculator
+	w.Base.On(uikit.EventPointerDown, w.onPointerDown, false)
+
+	return w
+}
+
+func (w *ListView) heightCalculator() int {
+	if w.height > 0 {
+		return w.height
+	}
+	w.sync()
+	return max(w.rowTop(w.n), w.Theme().ControlH)
+}
+
+func (w *ListView) Focusable() bool { return true }
+
+func (w *ListView) handlesEnter() bool { return w.OnActivate != nil }
+
+// SetHeight fixes the viewport height and makes the list scroll. Use 0 to
+// show every row.
+func (w *ListView) SetHeight(h int) {
+	w.height = h
+}
+
+func (w *ListView) Source() ListSource { return w.source }
+
+// Invalidate rebinds every row; call it after the source data changed.
+func (w *ListView) Invalidate() {
+	w.n = -1
+}
+
+func (w *ListView) SetSelectionMode(m SelectionMode) { w.sel.setMode(m) }
+
+// Selected returns the selected indexes in ascending order.
+func (w *ListView) Selected() []int { return w.sel.indexes() }
+
+func (w *ListView) IsSelected(i int) bool { return w.sel.selected[i] }
+
+// SetSelected replaces the selection. In single mode only the first index
+// is kept.
+func (w *ListView) SetSelected(indexes ...int) { w.sel.set(indexes) }
+
+func (w *ListView) ClearSelection() { clear(w.sel.selected) }
+
+// Cursor returns the index under the keyboard cursor, or -1.
+func (w *ListView) Cursor() int { return w.sel.cursor }
+
+// SetCursor moves the cursor to item i and scrolls it into view.
+func (w *ListView) SetCursor(i int) {
+	w.sync()
+	if i < 0 || i >= w.n {
+		return
+	}
+	w.sel.cursor = i
+	w.reveal = i
+}
+
+// ScrollToIndex scrolls the least amount needed to show item i.
+func (w *ListView) ScrollToIndex(i int) {
+	w.reveal = i
+}
+
+// sync follows the source length, releasing the rows past its end.
+func (w *ListView) sync() {
+	n := w.source.Len()
+	if n == w.n {
+		return
+	}
+
+	if w.n < 0 {
+		for i := range w.bound {
+			w.release(i)
+		}
+		w.heights = w.heights[:0]
+	}
+	for i := range w.bound {
+		if i >= n {
+			w.release(i)
+		}
+	}
+
+	if len(w.heights) > n {
+		w.heights = w.heights[:n]
+	}
+	for len(w.heights) < n {
+		w.heights = append(w.heights, 0)
+	}
+
+	w.n = n
+	w.offsets = nil
+	w.sel.clamp(n)
+}
+
+func (w *ListView) release(i int) {
+	row := w.bound[i]
+	delete(w.bound, i)
+	resetWidgetState(row)
+	w.free = append(w.free, row)
+}
+
+func (w *ListView) measured() bool { return w.RowHeight <= 0 }
+
+func (w *ListView) rowH(i int) int {
+	if !w.measured() {
+		return w.RowHeight
+	}
+	if h := w.heights[i]; h > 0 {
+		return h
+	}
+	return w.Theme().ControlH
+}
+
+// rowTop returns the content y of row i; rowTop(n) is the content height.
+func (w *ListView) rowTop(i int) int {
+	if !w.measured() {
+		return i * w.RowHeight
+	}
+
+	w.layoutOffsets()
+	return w.offsets[i]
+}
+
+// layoutOffsets recomputes the measured row tops when they are stale.
+func (w *ListView) layoutOffsets() {
+	if w.offsets != nil {
+		return
+	}
+
+	w.offsets = make([]int, w.n+1)
+	for j := 0; j < w.n; j++ {
+		w.offsets[j+1] = w.offsets[j] + w.rowH(j)
+	}
+}
+
+// indexAt returns the row at content y, clamped to the list.
+func (w *ListView) indexAt(y int) int {
+	if w.n == 0 {
+		return 0
+	}
+
+	var i int
+	if !w.measured() {
+		i = y / w.RowHeight
+	} else {
+		w.layoutOffsets()
+		i = sort.Search(w.n, func(j int) bool { return w.offsets[j+1] > y })
+	}
+	return clampInt(i, 0, w.n-1)
+}
+
+// viewport returns the list area on screen and the y of the first row.
+func (w *ListView) viewport() (image.Rectangle, int) {
+	r := w.Measure(false)
+	top := r.Min.Y
+	if w.height > 0 {
+		top -= w.scroll.ScrollY
+	}
+	return r, top
+}
+
+// itemAt returns the item under p, or -1.
+func (w *ListView) itemAt(p image.Point) int {
+	r, top := w.viewport()
+	if !p.In(r) || w.n == 0 || p.Y-top >= w.rowTop(w.n) {
+		return -1
+	}
+	return w.indexAt(p.Y - top)
+}
+
+func (w *ListView) changed() {
+	w.reveal = w.sel.cursor
+	w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange, Value: w.sel.cursor})
+}
+
+func (w *ListView) onPointerDown(e uikit.Event) bool {
+	if !w.IsEnabled() || e.Pointer == nil {
+		return false
+	}
+
+	if i := w.itemAt(e.Pointer.Position); i >= 0 {
+		w.sel.pick(i, shortcutPressed(), shiftPressed())
+		w.changed()
+	}
+	return false
+}
+
+func (w *ListView) Update(ctx *uikit.Context) {
+	r := w.Measure(false)
+	w.SetFrame(r.Min.X, r.Min.Y, r.Dx())
+	w.sync()
+
+	if w.IsEnabled() && w.IsFocused() {
+		page := 10
+		if w.height > 0 {
+			rh := w.RowHeight
+			if rh <= 0 {
+				rh = w.Theme().ControlH
+			}
+			page = max(w.height/rh-1, 1)
+		}
+
+		changed, activate := w.sel.handleKeys(w.n, page)
+		if changed {
+			w.changed()
+		}
+		if activate && w.OnActivate != nil {
+			w.OnActivate(w.sel.cursor)
+		}
+	}
+
+	if w.height > 0 {
+		contentH := w.rowTop(w.n)
+		w.scroll.Update(ctx, r, contentH)
+		if w.reveal >= 0 && w.reveal < w.n {
+			top := w.rowTop(w.reveal)
+			w.scroll.Reveal(top, top+w.rowH(w.reveal), r.Dy())
+		}
+		w.scroll.Clamp(r.Dy(), contentH)
+	}
+	w.reveal = -1
+
+	w.bindVisible(ctx)
+}
+
+// bindVisible binds, lays out and updates the rows in view, recycling the
+// others.
+func (w *ListView) bindVisible(ctx *uikit.Context) {
+	r, top := w.viewport()
+	view := r
+	if s := ctx.Screen(); !s.Empty() {
+		view = r.Intersect(s)
+	}
+
+	first, last := 0, 0
+	if w.n > 0 && !view.Empty() {
+		first = w.indexAt(view.Min.Y - top)
+		last = w.indexAt(view.Max.Y-1-top) + 1
+	}
+
+	for i := range w.bound {
+		if i < first || i >= last {
+			w.release(i)
+		}
+	}
+
+	hover := -1
+	if w.IsHovered() && w.IsEnabled() {
+		hover = w.itemAt(ctx.Pointer().Position)
+	}
+
+	pad := w.Theme().SpaceS
+	for i := first; i < last; i++ {
+		row := w.bound[i]
+		if row == nil {
+			if k := len(w.free); k > 0 {
+				row, w.free = w.free[k-1], w.free[:k-1]
+			} else {
+				row = w.source.NewRow()
+			}
+			w.source.BindRow(row, i)
+			w.bound[i] = row
+		}
+
+		row.SetFrame(r.Min.X+pad, top+w.rowTop(i), max(r.Dx()-pad*2, 0))
+		row.SetHovered(i == hover)
+		row.Update(ctx)
+
+		if w.measured() {
+			if h := row.Measure(true).Dy(); h > 0 && h != w.heights[i] {
+				w.heights[i] = h
+				w.offsets = nil
+			}
+		}
+	}
+}
+
+func (w *ListView) Draw(ctx *uikit.Context, dst *ebiten.Image) {
+	theme := ctx.Theme()
+	r, top := w.viewport()
+	clip := r.Intersect(dst.Bounds())
+	if clip.Empty() {
+		return
+	}
+
+	bg := theme.SurfaceColor
+	if !w.IsEnabled() {
+		bg = theme.SurfacePressedColor
+	}
+	if w.height > 0 {
+		w.DrawRoundedRect(dst, r, theme.Radius, bg)
+	} else {
+		w.DrawRoundedRect(dst, clip, 0, bg)
+	}
+
+	sub := dst.SubImage(clip).(*ebiten.Image)
+	for i, row := range w.bound {
+		y := top + w.rowTop(i)
+		slot := image.Rect(r.Min.X, y, r.Max.X, y+w.rowH(i))
+		if !slot.Overlaps(clip) {
+			continue
+		}
+
+		switch {
+		case w.sel.selected[i]:
+			w.DrawRoundedRect(sub, slot, 0, theme.SelectionColor)
+		case row.IsHovered():
+			w.DrawRoundedRect(sub, slot, 0, theme.SurfaceHoverColor)
+		}
+
+		row.Draw(ctx, sub)
+
+		if i == w.sel.cursor && w.IsFocused() && w.IsEnabled() {
+			w.DrawRoundedBorder(sub, slot, theme.Radius, theme.FocusRingW, theme.FocusColor)
+		}
+	}
+
+	if w.height > 0 {
+		w.DrawRoundedBorder(dst, r, theme.Radius, theme.BorderW, theme.BorderColor)
+		if w.IsFocused() && w.IsEnabled() && w.sel.cursor < 0 {
+			w.DrawRoundedBorder(dst, r, theme.Radius, theme.FocusRingW, theme.FocusColor)
+		}
+		w.scroll.DrawBar(sub, theme, clip.Dx(), clip.Dy(), w.rowTop(w.n))
+	}
+}
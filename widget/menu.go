@@ -0,0 +1,636 @@
+package widget
+
+import (
+	"image"
+
+	"github.com/erparts/go-uikit"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/tinne26/etxt"
+)
+
+var _ uikit.ModalWidget = (*Menu)(nil)
+var _ uikit.Hittable = (*Menu)(nil)
+
+// MenuItem is an entry of a Menu.
+type MenuItem struct {
+	Label string
+	// Accel is the shortcut shown on the trailing side (e.g. "Ctrl+S"). It is
+	// only displayed: binding the keys is up to the application.
+	Accel string
+
+	// Icon is drawn in the check column of items that are not checked.
+	Icon *uikit.Icon
+
+	// Checkable items toggle Checked when selected.
+	Checkable bool
+	Checked   bool
+	Disabled  bool
+
+	// Submenu opens on hover or Right instead of selecting the item.
+	Submenu *Menu
+
+	OnSelect func(item *MenuItem)
+
+	separator bool
+}
+
+// MenuSeparator returns a separator line item.
+func MenuSeparator() *MenuItem {
+	return &MenuItem{separator: true}
+}
+
+func (it *MenuItem) IsSeparator() bool { return it.separator }
+
+func (it *MenuItem) selectable() bool { return !it.separator && !it.Disabled }
+
+// Menu is a popup list of commands (context menus, drop-down menus).
+// - It opens at a point (OpenAt) or below a widget (OpenFor) through
+// Context.ShowPopup, and draws in the overlay pass like the Select list.
+// - Panels are moved or flipped so they never leave the screen.
+// - Submenus open on hover or Right (Left in RTL) and close with Left or
+// Escape.
+// - A click outside the menu, Escape on the top-level menu or selecting an
+// item closes the whole menu.
+type Menu struct {
+	uikit.Base
+
+	items []*MenuItem
+
+	// OnClose is called when the menu closes, selected or not.
+	OnClose func()
+
+	open     bool
+	closing  bool
+	selected *MenuItem
+
+	parent    *Menu
+	parentRow int
+	child     *Menu
+	hot       int // highlighted item, -1 if none
+
+	anchor   image.Rectangle // point (empty rect) or widget rect
+	anchored bool
+
+	panel   image.Rectangle
+	rows    []image.Rectangle
+	armed   bool // a press started inside the menu
+	lastPtr image.Point
+
+	bar *MenuBar // set when opened from a MenuBar
+}
+
+func NewMenu(theme *uikit.Theme) *Menu {
+	cfg := uikit.NewWidgetBaseConfig(theme)
+	cfg.DrawSurface = false
+	cfg.DrawBorder = false
+	cfg.DrawFocus = false
+
+	m := &Menu{
+		Base: uikit.NewBase(cfg),
+		hot:  -1,
+	}
+	m.Base.HeightCalculator = func() int { return m.panel.Dy() }
+
+	return m
+}
+
+func (m *Menu) Focusable() bool { return m.open }
+
+// Add appends items.
+func (m *Menu) Add(items ...*MenuItem) {
+	m.items = append(m.items, items...)
+}
+
+// AddItem appends an item calling fn when selected.
+func (m *Menu) AddItem(label string, fn func()) *MenuItem {
+	it := &MenuItem{Label: label}
+	if fn != nil {
+		it.OnSelect = func(*MenuItem) { fn() }
+	}
+	m.items = append(m.items, it)
+	return it
+}
+
+func (m *Menu) AddSeparator() {
+	m.items = append(m.items, MenuSeparator())
+}
+
+// AddSubmenu appends an item opening a new submenu and returns the submenu.
+func (m *Menu) AddSubmenu(label string) *Menu {
+	sub := NewMenu(m.Theme())
+	m.items = append(m.items, &MenuItem{Label: label, Submenu: sub})
+	return sub
+}
+
+func (m *Menu) Items() []*MenuItem { return m.items }
+
+func (m *Menu) Clear() {
+	m.items = nil
+	m.hot = -1
+}
+
+func (m *Menu) IsOpen() bool { return m.open }
+
+// OpenAt opens the menu with its top leading corner at p (e.g. the pointer).
+func (m *Menu) OpenAt(ctx *uikit.Context, p image.Point) {
+	m.anchor, m.anchored = image.Rectangle{Min: p, Max: p}, false
+	m.show(ctx)
+}
+
+// OpenFor opens the menu below w, or above it when there is no room.
+func (m *Menu) OpenFor(ctx *uikit.Context, w uikit.Widget) {
+	m.anchor, m.anchored = w.Measure(false), true
+	m.show(ctx)
+}
+
+// openRect opens the menu below r (used by MenuBar).
+func (m *Menu) openRect(ctx *uikit.Context, r image.Rectangle) {
+	m.anchor, m.anchored = r, true
+	m.show(ctx)
+}
+
+func (m *Menu) show(ctx *uikit.Context) {
+	m.open, m.closing, m.selected = true, false, nil
+	m.parent, m.child, m.hot = nil, nil, -1
+	m.armed, m.bar = false, nil
+	m.lastPtr = ctx.Pointer().Position
+
+	ctx.ShowPopup(m)
+	m.layout(ctx.Theme(), ctx.Screen(), m.IsRTL(ctx))
+}
+
+// Close closes the whole menu on its next Update.
+func (m *Menu) Close() {
+	m.root().closing = true
+}
+
+func (m *Menu) root() *Menu {
+	for m.parent != nil {
+		m = m.parent
+	}
+	return m
+}
+
+// deepest returns the innermost open submenu.
+func (m *Menu) deepest() *Menu {
+	for m.child != nil {
+		m = m.child
+	}
+	return m
+}
+
+func (m *Menu) openChild(i int) {
+	sub := m.items[i].Submenu
+	if m.child == sub {
+		return
+	}
+
+	m.closeChild()
+	sub.open, sub.parent, sub.parentRow = true, m, i
+	sub.child, sub.hot = nil, -1
+	m.child = sub
+}
+
+func (m *Menu) closeChild() {
+	if m.child == nil {
+		return
+	}
+
+	m.child.closeChild()
+	m.child.open, m.child.parent = false, nil
+	m.child = nil
+}
+
+// moveHot moves the highlight to the next selectable item in dir, wrapping.
+func (m *Menu) moveHot(dir int) {
+	n := len(m.items)
+	i := m.hot
+	if i < 0 && dir < 0 {
+		i = n
+	}
+	for k := 0; k < n; k++ {
+		i = (i + dir + n) % n
+		if m.items[i].selectable() {
+			m.hot = i
+			return
+		}
+	}
+}
+
+// activate selects item i: submenus open, other items close the menu.
+func (m *Menu) activate(i int) {
+	if i < 0 || i >= len(m.items) || !m.items[i].selectable() {
+		return
+	}
+
+	it := m.items[i]
+	if it.Submenu != nil {
+		m.openChild(i)
+		m.child.moveHot(1)
+		return
+	}
+
+	if it.Checkable {
+		it.Checked = !it.Checked
+	}
+
+	r := m.root()
+	r.selected, r.closing = it, true
+}
+
+// Metrics shared by the panels.
+func menuMetrics(theme *uikit.Theme) (rowH, sepH, padY, checkW, arrowW int) {
+	return theme.ControlH, theme.SpaceS + theme.BorderW, theme.SpaceS / 2, theme.ControlH * 3 / 4, theme.ControlH / 2
+}
+
+func (m *Menu) panelSize(theme *uikit.Theme) (int, int) {
+	rowH, sepH, padY, checkW, arrowW := menuMetrics(theme)
+	t := theme.Text()
+
+	labelW, accelW, h := 0, 0, padY*2
+	for _, it := range m.items {
+		if it.separator {
+			h += sepH
+			continue
+		}
+
+		h += rowH
+		label, _, _ := parseMnemonic(it.Label)
+		labelW = max(labelW, t.Measure(label).IntWidth())
+		if it.Accel != "" {
+			accelW = max(accelW, t.Measure(it.Accel).IntWidth())
+		}
+	}
+
+	w := theme.PadX*2 + checkW + labelW + arrowW
+	if accelW > 0 {
+		w += theme.SpaceL + accelW
+	}
+	return max(w, theme.ControlH*5), h
+}
+
+// fitRect moves r inside screen, keeping its size when possible.
+func fitRect(r, screen image.Rectangle) image.Rectangle {
+	if screen.Empty() {
+		return r
+	}
+
+	dx, dy := 0, 0
+	if r.Max.X > screen.Max.X {
+		dx = screen.Max.X - r.Max.X
+	}
+	if r.Min.X+dx < screen.Min.X {
+		dx = screen.Min.X - r.Min.X
+	}
+	if r.Max.Y > screen.Max.Y {
+		dy = screen.Max.Y - r.Max.Y
+	}
+	if r.Min.Y+dy < screen.Min.Y {
+		dy = screen.Min.Y - r.Min.Y
+	}
+	return r.Add(image.Pt(dx, dy))
+}
+
+// placeRect picks the first candidate that fits the screen, or moves the first
+// one inside it.
+func placeRect(screen image.Rectangle, candidates ...image.Rectangle) image.Rectangle {
+	for _, c := range candidates {
+		if screen.Empty() || c.In(screen) {
+			return c
+		}
+	}
+	return fitRect(candidates[0], screen)
+}
+
+// layout positions the panel of m and of its open submenus.
+func (m *Menu) layout(theme *uikit.Theme, screen image.Rectangle, rtl bool) {
+	w, h := m.panelSize(theme)
+	size := image.Rect(0, 0, w, h)
+
+	// Leading-side placement first, flipped when it does not fit.
+	var pref, alt image.Rectangle
+	switch {
+	case m.parent != nil:
+		_, _, padY, _, _ := menuMetrics(theme)
+		row := m.parent.rows[m.parentRow]
+		pp := m.parent.panel
+		after := size.Add(image.Pt(pp.Max.X-theme.BorderW, row.Min.Y-padY))
+		before := size.Add(image.Pt(pp.Min.X-w+theme.BorderW, row.Min.Y-padY))
+		pref, alt = after, before
+		if rtl {
+			pref, alt = before, after
+		}
+		m.panel = placeRect(screen, pref, alt)
+	case m.anchored:
+		a := m.anchor
+		x := a.Min.X
+		if rtl {
+			x = a.Max.X - w
+		}
+		below := size.Add(image.Pt(x, a.Max.Y))
+		above := size.Add(image.Pt(x, a.Min.Y-h))
+		m.panel = placeRect(screen, fitRectX(below, screen), fitRectX(above, screen))
+	default:
+		p := m.anchor.Min
+		after, before := p.X, p.X-w
+		if rtl {
+			after, before = before, after
+		}
+		var cands []image.Rectangle
+		for _, x := range []int{after, before} {
+			cands = append(cands, size.Add(image.Pt(x, p.Y)), size.Add(image.Pt(x, p.Y-h)))
+		}
+		m.panel = placeRect(screen, cands...)
+	}
+
+	m.SetFrame(m.panel.Min.X, m.panel.Min.Y, m.panel.Dx())
+
+	rowH, sepH, padY, _, _ := menuMetrics(theme)
+	m.rows = m.rows[:0]
+	y := m.panel.Min.Y + padY
+	for _, it := range m.items {
+		rh := rowH
+		if it.separator {
+			rh = sepH
+		}
+		m.rows = append(m.rows, image.Rect(m.panel.Min.X, y, m.panel.Max.X, y+rh))
+		y += rh
+	}
+
+	if m.child != nil {
+		m.child.layout(theme, screen, rtl)
+	}
+}
+
+// fitRectX moves r horizontally inside screen.
+func fitRectX(r, screen image.Rectangle) image.Rectangle {
+	f := fitRect(r, screen)
+	return image.Rect(f.Min.X, r.Min.Y, f.Max.X, r.Max.Y)
+}
+
+// menuAt returns the innermost open panel containing p, or nil.
+func (m *Menu) menuAt(p image.Point) *Menu {
+	for x := m.deepest(); x != nil; x = x.parent {
+		if p.In(x.panel) {
+			return x
+		}
+	}
+	return nil
+}
+
+func (m *Menu) rowAt(p image.Point) int {
+	for i, r := range m.rows {
+		if p.In(r) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *Menu) HitTest(ctx *uikit.Context, pos image.Point) bool {
+	return m.open && m.menuAt(pos) != nil
+}
+
+func (m *Menu) Children() []uikit.Widget { return nil }
+
+func (m *Menu) InitialFocus() uikit.Widget { return m }
+
+func (m *Menu) Update(ctx *uikit.Context) {
+	if !m.open {
+		return
+	}
+
+	rtl := m.IsRTL(ctx)
+	m.layout(ctx.Theme(), ctx.Screen(), rtl)
+
+	if m.bar != nil && m.bar.updateOpen(ctx, m, rtl) {
+		return
+	}
+
+	m.updatePointer(ctx)
+	if !m.closing {
+		m.updateKeys(rtl)
+	}
+
+	if m.closing {
+		m.finish(ctx)
+	}
+}
+
+func (m *Menu) updatePointer(ctx *uikit.Context) {
+	ptr := ctx.Pointer()
+	hit := m.menuAt(ptr.Position)
+
+	// Hover follows the pointer only when it moves, so it does not fight
+	// the keyboard.
+	if hit != nil && (ptr.Position != m.lastPtr || ptr.IsJustDown) {
+		i := hit.rowAt(ptr.Position)
+		if i >= 0 && !hit.items[i].selectable() {
+			i = -1
+		}
+
+		hit.hot = i
+		if i >= 0 && hit.items[i].Submenu != nil {
+			hit.openChild(i)
+		} else if hit.child != nil && i >= 0 {
+			hit.closeChild()
+		}
+	}
+	m.lastPtr = ptr.Position
+
+	if ptr.IsJustDown {
+		if hit == nil {
+			m.closing = true
+			return
+		}
+		m.armed = true
+	}
+
+	// Items trigger on release, so press-drag-release works too.
+	if ptr.IsJustUp && m.armed {
+		m.armed = false
+		if hit != nil {
+			hit.activate(hit.rowAt(ptr.Position))
+		}
+	}
+}
+
+func (m *Menu) updateKeys(rtl bool) {
+	d := m.deepest()
+
+	into, out := ebiten.KeyRight, ebiten.KeyLeft
+	if rtl {
+		into, out = out, into
+	}
+
+	switch {
+	case keyRepeat(ebiten.KeyDown):
+		d.moveHot(1)
+	case keyRepeat(ebiten.KeyUp):
+		d.moveHot(-1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyHome):
+		d.hot = -1
+		d.moveHot(1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnd):
+		d.hot = -1
+		d.moveHot(-1)
+	case inpututil.IsKeyJustPressed(into):
+		if d.hot >= 0 && d.items[d.hot].Submenu != nil {
+			d.activate(d.hot)
+		}
+	case inpututil.IsKeyJustPressed(out):
+		if d.parent != nil {
+			d.parent.closeChild()
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyKPEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace):
+		d.activate(d.hot)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		if d.parent != nil {
+			d.parent.closeChild()
+		} else {
+			m.closing = true
+		}
+	default:
+		if i := d.mnemonicItem(); i >= 0 {
+			d.hot = i
+			d.activate(i)
+		}
+	}
+}
+
+// mnemonicItem returns the selectable item whose mnemonic was just typed.
+func (m *Menu) mnemonicItem() int {
+	if shortcutPressed() {
+		return -1
+	}
+
+	for _, k := range inpututil.AppendJustPressedKeys(nil) {
+		r := keyLetter(k)
+		if r == 0 {
+			continue
+		}
+		for i, it := range m.items {
+			if _, key, _ := parseMnemonic(it.Label); key == r && it.selectable() {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// closeNow closes the menu without selecting anything.
+func (m *Menu) closeNow(ctx *uikit.Context) {
+	m.selected = nil
+	m.finish(ctx)
+}
+
+// finish closes the menu, then runs the selected item callback (which may
+// open a dialog or another menu).
+func (m *Menu) finish(ctx *uikit.Context) {
+	it := m.selected
+	m.closeChild()
+	m.open, m.closing, m.selected, m.armed = false, false, nil, false
+	ctx.ClosePopup(m)
+
+	if m.OnClose != nil {
+		m.OnClose()
+	}
+	if it != nil && it.OnSelect != nil {
+		it.OnSelect(it)
+	}
+}
+
+func (m *Menu) Draw(ctx *uikit.Context, dst *ebiten.Image) {}
+
+func (m *Menu) DrawOverlay(ctx *uikit.Context, dst *ebiten.Image) {
+	if !m.open {
+		return
+	}
+
+	theme := ctx.Theme()
+	rtl := m.IsRTL(ctx)
+	for x := m; x != nil; x = x.child {
+		x.drawPanel(theme, dst, rtl)
+	}
+}
+
+func (m *Menu) drawPanel(theme *uikit.Theme, dst *ebiten.Image, rtl bool) {
+	if len(m.rows) != len(m.items) {
+		return
+	}
+
+	m.DrawRoundedRect(dst, m.panel, theme.Radius, theme.SurfaceColor)
+	m.DrawRoundedBorder(dst, m.panel, theme.Radius, theme.BorderW, theme.BorderColor)
+
+	_, _, _, checkW, arrowW := menuMetrics(theme)
+	t := theme.Text()
+
+	for i, it := range m.items {
+		row := m.rows[i]
+		if it.separator {
+			y := row.Min.Y + row.Dy()/2
+			line := image.Rect(row.Min.X+theme.PadX, y, row.Max.X-theme.PadX, y+theme.BorderW)
+			m.DrawRoundedRect(dst, line, 0, theme.BorderColor)
+			continue
+		}
+
+		hot := i == m.hot || (m.child != nil && i == m.child.parentRow)
+		if hot && !it.Disabled {
+			m.DrawRoundedRect(dst, row.Inset(theme.BorderW), theme.Radius, theme.SurfaceHoverColor)
+		}
+
+		textCol, accelCol := theme.TextColor, theme.MutedTextColor
+		if it.Disabled {
+			textCol, accelCol = theme.DisabledColor, theme.DisabledColor
+		}
+
+		// Columns from the leading edge: check, label ... accel, arrow.
+		lead, trail := row.Min.X+theme.PadX, row.Max.X-theme.PadX
+		if rtl {
+			lead, trail = trail, lead
+		}
+		dir := 1
+		if rtl {
+			dir = -1
+		}
+		cy := row.Min.Y + row.Dy()/2
+
+		if it.Checkable && it.Checked {
+			size := checkW * 2 / 3
+			bx := lead
+			if rtl {
+				bx = lead - size
+			}
+			box := image.Rect(bx, cy-size/2, bx+size, cy-size/2+size)
+			drawCheckMark(theme, dst, box, float32(max(theme.BorderW, 2)), textCol)
+		} else if it.Icon != nil {
+			size := min(theme.IconSize, checkW)
+			bx := lead
+			if rtl {
+				bx = lead - size
+			}
+			it.Icon.Draw(dst, image.Rect(bx, cy-size/2, bx+size, cy-size/2+size), textCol)
+		}
+
+		align := etxt.Left
+		if rtl {
+			align = etxt.Right
+		}
+		drawMnemonicLabel(theme, dst, it.Label, lead+dir*checkW, cy, align, textCol, true, rtl)
+
+		if it.Accel != "" {
+			t.SetColor(accelCol)
+			if rtl {
+				t.SetAlign(etxt.Left | etxt.VertCenter)
+			} else {
+				t.SetAlign(etxt.Right | etxt.VertCenter)
+			}
+			t.Draw(dst, it.Accel, trail-dir*arrowW, cy)
+		}
+
+		if it.Submenu != nil {
+			drawChevron(dst, float32(trail-dir*arrowW/2), float32(cy), float32(arrowW)/2, float32(dir), 0, textCol)
+		}
+	}
+}
@@ -0,0 +1,392 @@
+package widget
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/erparts/go-uikit"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/tinne26/etxt"
+)
+
+var _ uikit.Widget = (*MenuBar)(nil)
+
+// MenuBar is the row of top-level menus of a desktop tool (File, Edit...),
+// meant as the first child of the root Stack.
+// - Clicking a title opens its menu; while it is open, hovering another
+// title switches to that menu, and Left/Right move between menus.
+// - Titles take '&' mnemonics ("&File"): Alt+F opens the menu. Menu items
+// use the same syntax, typing the letter selects the item.
+// - Tapping Alt enters keyboard mode: arrows move between titles, Enter or
+// Down opens the highlighted menu, Escape leaves.
+// - Escape or a click outside closes the open menu.
+type MenuBar struct {
+	uikit.Base
+
+	titles []string
+	menus  []*Menu
+	rects  []image.Rectangle
+
+	hot       int // title highlighted in keyboard mode
+	keyboard  bool
+	prevFocus uikit.Widget
+	altArmed  bool // Alt went down with no other key (yet)
+}
+
+func NewMenuBar(theme *uikit.Theme) *MenuBar {
+	cfg := uikit.NewWidgetBaseConfig(theme)
+	cfg.DrawSurface = false
+	cfg.DrawBorder = false
+	cfg.DrawFocus = false // drawn around the highlighted title instead
+
+	return &MenuBar{Base: uikit.NewBase(cfg)}
+}
+
+// Focusable is only true in keyboard mode: the bar is not a Tab stop.
+func (w *MenuBar) Focusable() bool { return w.keyboard }
+
+// AddMenu appends a new menu under title and returns it.
+func (w *MenuBar) AddMenu(title string) *Menu {
+	m := NewMenu(w.Theme())
+	w.Add(title, m)
+	return m
+}
+
+// Add appends m under title.
+func (w *MenuBar) Add(title string, m *Menu) {
+	w.titles = append(w.titles, title)
+	w.menus = append(w.menus, m)
+}
+
+func (w *MenuBar) Menus() []*Menu { return w.menus }
+
+// layout computes the title rects from the leading edge.
+func (w *MenuBar) layout(theme *uikit.Theme, rtl bool) {
+	r := w.Measure(false)
+	t := theme.Text()
+
+	w.rects = w.rects[:0]
+	x := 0
+	for _, title := range w.titles {
+		text, _, _ := parseMnemonic(title)
+		tw := t.Measure(text).IntWidth() + theme.PadX*2
+
+		rx := r.Min.X + x
+		if rtl {
+			rx = r.Max.X - x - tw
+		}
+		w.rects = append(w.rects, image.Rect(rx, r.Min.Y, rx+tw, r.Max.Y))
+		x += tw
+	}
+}
+
+func (w *MenuBar) titleAt(p image.Point) int {
+	for i, r := range w.rects {
+		if p.In(r) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (w *MenuBar) openIndex() int {
+	for i, m := range w.menus {
+		if m.IsOpen() {
+			return i
+		}
+	}
+	return -1
+}
+
+func (w *MenuBar) mnemonicMenu(k ebiten.Key) int {
+	r := keyLetter(k)
+	if r == 0 {
+		return -1
+	}
+	for i, title := range w.titles {
+		if _, key, _ := parseMnemonic(title); key == r {
+			return i
+		}
+	}
+	return -1
+}
+
+func (w *MenuBar) setKeyboard(ctx *uikit.Context, v bool) {
+	if v == w.keyboard || len(w.menus) == 0 {
+		return
+	}
+
+	if v {
+		w.prevFocus = ctx.Focused()
+		w.keyboard, w.hot = true, 0
+		ctx.SetFocus(w)
+		return
+	}
+
+	w.keyboard = false
+	ctx.SetFocus(w.prevFocus)
+	w.prevFocus = nil
+}
+
+// open shows menu i; from the keyboard its first item is highlighted.
+func (w *MenuBar) open(ctx *uikit.Context, i int, keyboard bool) {
+	// The focus goes back where it was, so it returns there once the menu
+	// closes.
+	w.setKeyboard(ctx, false)
+
+	m := w.menus[i]
+	m.openRect(ctx, w.rects[i])
+	m.bar = w
+	if keyboard {
+		m.moveHot(1)
+	}
+}
+
+func (w *MenuBar) Update(ctx *uikit.Context) {
+	r := w.Measure(false)
+	if r.Dy() == 0 {
+		w.SetFrame(r.Min.X, r.Min.Y, r.Dx())
+	}
+	w.layout(ctx.Theme(), w.IsRTL(ctx))
+
+	if !w.IsEnabled() || len(w.menus) == 0 {
+		return
+	}
+
+	ptr := ctx.Pointer()
+	if ptr.IsJustDown {
+		if i := w.titleAt(ptr.Position); i >= 0 {
+			w.open(ctx, i, false)
+			return
+		}
+		w.setKeyboard(ctx, false)
+	}
+
+	if w.updateAlt(ctx) {
+		return
+	}
+
+	if !w.keyboard {
+		return
+	}
+	if ctx.Focused() != w {
+		w.keyboard, w.prevFocus = false, nil
+		return
+	}
+
+	prev, next := ebiten.KeyLeft, ebiten.KeyRight
+	if w.IsRTL(ctx) {
+		prev, next = next, prev
+	}
+
+	n := len(w.menus)
+	switch {
+	case keyRepeat(next):
+		w.hot = (w.hot + 1) % n
+	case keyRepeat(prev):
+		w.hot = (w.hot - 1 + n) % n
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace) || inpututil.IsKeyJustPressed(ebiten.KeyDown):
+		w.open(ctx, w.hot, true)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		w.setKeyboard(ctx, false)
+	}
+}
+
+// updateAlt handles Alt+mnemonic and the Alt tap toggling keyboard mode.
+// It reports whether a menu was opened.
+func (w *MenuBar) updateAlt(ctx *uikit.Context) bool {
+	if inpututil.IsKeyJustPressed(ebiten.KeyAlt) {
+		w.altArmed = true
+	}
+
+	if ebiten.IsKeyPressed(ebiten.KeyAlt) {
+		for _, k := range inpututil.AppendJustPressedKeys(nil) {
+			if k == ebiten.KeyAltLeft || k == ebiten.KeyAltRight {
+				continue
+			}
+
+			w.altArmed = false
+			if i := w.mnemonicMenu(k); i >= 0 {
+				w.open(ctx, i, true)
+				return true
+			}
+		}
+	}
+
+	if inpututil.IsKeyJustReleased(ebiten.KeyAlt) && w.altArmed {
+		w.altArmed = false
+		w.setKeyboard(ctx, !w.keyboard)
+	}
+	return false
+}
+
+// updateOpen runs from the Update of the open menu m, since the bar itself
+// is not updated while a popup is shown. It switches menus on hover, clicks
+// on the bar and Left/Right, and reports whether m was closed.
+func (w *MenuBar) updateOpen(ctx *uikit.Context, m *Menu, rtl bool) bool {
+	cur := -1
+	for i, x := range w.menus {
+		if x == m {
+			cur = i
+		}
+	}
+	if cur < 0 {
+		return false
+	}
+
+	ptr := ctx.Pointer()
+	if i := w.titleAt(ptr.Position); i >= 0 {
+		switch {
+		case ptr.IsJustDown && i == cur:
+			m.closeNow(ctx)
+			return true
+		case ptr.IsJustDown || (i != cur && ptr.Position != m.lastPtr):
+			m.closeNow(ctx)
+			w.open(ctx, i, false)
+			return true
+		}
+	}
+
+	prev, next := ebiten.KeyLeft, ebiten.KeyRight
+	if rtl {
+		prev, next = next, prev
+	}
+
+	n := len(w.menus)
+	d := m.deepest()
+	step := 0
+	switch {
+	case inpututil.IsKeyJustPressed(prev) && d == m:
+		step = -1
+	case inpututil.IsKeyJustPressed(next) && (d.hot < 0 || d.items[d.hot].Submenu == nil):
+		step = 1
+	case ebiten.IsKeyPressed(ebiten.KeyAlt):
+		for _, k := range inpututil.AppendJustPressedKeys(nil) {
+			if i := w.mnemonicMenu(k); i >= 0 && i != cur {
+				m.closeNow(ctx)
+				w.open(ctx, i, true)
+				return true
+			}
+		}
+	}
+
+	if step != 0 && n > 1 {
+		m.closeNow(ctx)
+		w.open(ctx, (cur+step+n)%n, true)
+		return true
+	}
+	return false
+}
+
+func (w *MenuBar) Draw(ctx *uikit.Context, dst *ebiten.Image) {
+	w.Base.Draw(ctx, dst)
+
+	theme := ctx.Theme()
+	r := w.Measure(false)
+	rtl := w.IsRTL(ctx)
+
+	w.DrawRoundedRect(dst, r, 0, theme.SurfaceColor)
+	line := image.Rect(r.Min.X, r.Max.Y-theme.BorderW, r.Max.X, r.Max.Y)
+	w.DrawRoundedRect(dst, line, 0, theme.BorderColor)
+
+	if len(w.rects) != len(w.titles) {
+		return
+	}
+
+	ptr := ctx.Pointer()
+	open := w.openIndex()
+	underline := w.keyboard || ebiten.IsKeyPressed(ebiten.KeyAlt)
+
+	for i, title := range w.titles {
+		tr := w.rects[i]
+
+		switch {
+		case i == open:
+			w.DrawRoundedRect(dst, tr, theme.Radius, theme.SurfacePressedColor)
+		case w.keyboard && i == w.hot:
+			w.DrawRoundedRect(dst, tr, theme.Radius, theme.SurfaceHoverColor)
+			w.DrawRoundedBorder(dst, tr, theme.Radius, theme.FocusRingW, theme.FocusColor)
+		case w.IsHovered() && ptr.Position.In(tr):
+			w.DrawRoundedRect(dst, tr, theme.Radius, theme.SurfaceHoverColor)
+		}
+
+		col := theme.TextColor
+		if !w.IsEnabled() {
+			col = theme.DisabledColor
+		}
+		drawMnemonicLabel(theme, dst, title, tr.Min.X+tr.Dx()/2, tr.Min.Y+tr.Dy()/2, etxt.HorzCenter, col, underline, rtl)
+	}
+}
+
+// parseMnemonic strips the '&' marking the mnemonic letter of a label
+// ("&File", "Save &As") and returns the letter in lower case (0 if none)
+// and its byte offset in text. "&&" is a literal '&'.
+func parseMnemonic(label string) (text string, key rune, at int) {
+	if !strings.Contains(label, "&") {
+		return label, 0, -1
+	}
+
+	var b strings.Builder
+	at = -1
+	for i := 0; i < len(label); i++ {
+		if label[i] != '&' || i == len(label)-1 {
+			b.WriteByte(label[i])
+			continue
+		}
+
+		i++
+		if label[i] != '&' && key == 0 {
+			r, _ := utf8.DecodeRuneInString(label[i:])
+			key, at = unicode.ToLower(r), b.Len()
+		}
+		b.WriteByte(label[i])
+	}
+	return b.String(), key, at
+}
+
+// keyLetter returns the lower-case letter or digit of k, or 0.
+func keyLetter(k ebiten.Key) rune {
+	switch {
+	case k >= ebiten.KeyA && k <= ebiten.KeyZ:
+		return 'a' + rune(k-ebiten.KeyA)
+	case k >= ebiten.KeyDigit0 && k <= ebiten.KeyDigit9:
+		return '0' + rune(k-ebiten.KeyDigit0)
+	}
+	return 0
+}
+
+// drawMnemonicLabel draws a label with its '&' mnemonic removed and, if
+// underline is set, the mnemonic letter underlined (LTR text only, where
+// the glyph positions are known).
+func drawMnemonicLabel(theme *uikit.Theme, dst *ebiten.Image, label string, x, y int, align etxt.Align, col color.RGBA, underline, rtl bool) {
+	text, key, at := parseMnemonic(label)
+
+	t := theme.Text()
+	t.SetColor(col)
+	t.SetAlign(align | etxt.VertCenter)
+	t.Draw(dst, visualText(text, rtl), x, y)
+
+	if !underline || key == 0 || rtl || needsBidi(text) {
+		return
+	}
+
+	w := t.Measure(text).IntWidth()
+	left := x
+	switch align.Horz() {
+	case etxt.Right:
+		left = x - w
+	case etxt.HorzCenter:
+		left = x - w/2
+	}
+
+	_, size := utf8.DecodeRuneInString(text[at:])
+	x0 := left + t.Measure(text[:at]).IntWidth()
+	x1 := left + t.Measure(text[:at+size]).IntWidth()
+	uy := y + theme.FontPx/2
+	vector.DrawFilledRect(dst, float32(x0), float32(uy), float32(x1-x0), float32(max(theme.FontPx/12, 1)), col, false)
+}
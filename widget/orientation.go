@@ -0,0 +1,9 @@
+package widget
+
+// Orientation is the main axis of widgets that can lay out either way.
+type Orientation int
+
+const (
+	Horizontal Orientation = iota
+	Vertical
+)
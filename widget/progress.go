@@ -0,0 +1,250 @@
+package widget
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"time"
+
+	"github.com/erparts/go-uikit"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/tinne26/etxt"
+)
+
+var _ uikit.Widget = (*ProgressBar)(nil)
+var _ uikit.Widget = (*Spinner)(nil)
+
+const (
+	progressSweep = 1500 * time.Millisecond // indeterminate sweep period
+	spinnerTurn   = 900 * time.Millisecond  // spinner revolution period
+	spinnerDots   = 8
+)
+
+// ProgressBar shows the progress of a long operation.
+// - Determinate: a 0..1 value, optionally printed inside the bar ("42%").
+// - Indeterminate: a segment sweeping along the track.
+// Animations follow Context.Now, not the frame count.
+type ProgressBar struct {
+	uikit.Base
+
+	value         float64
+	indeterminate bool
+	start         time.Time
+
+	// ShowText prints the value inside the bar (determinate mode only).
+	ShowText bool
+
+	// Format renders the value for ShowText. Defaults to "42%".
+	Format func(v float64) string
+}
+
+func NewProgressBar(theme *uikit.Theme) *ProgressBar {
+	cfg := uikit.NewWidgetBaseConfig(theme)
+	cfg.DrawSurface = false
+	cfg.DrawBorder = false
+	cfg.DrawFocus = false
+
+	w := &ProgressBar{Base: uikit.NewBase(cfg)}
+	w.Base.HeightCalculator = w.calculateHeight
+	return w
+}
+
+// The bar is half a control high, or a full control when it holds text.
+func (w *ProgressBar) calculateHeight() int {
+	if w.ShowText {
+		return w.Theme().ControlH
+	}
+	return max(w.Theme().ControlH/2, 4)
+}
+
+func (w *ProgressBar) Focusable() bool { return false }
+
+// SetValue sets the progress, clamped to 0..1.
+func (w *ProgressBar) SetValue(v float64) {
+	w.value = math.Max(0, math.Min(1, v))
+}
+
+func (w *ProgressBar) Value() float64 { return w.value }
+
+// SetIndeterminate switches to the sweeping animation, for operations whose
+// length is unknown.
+func (w *ProgressBar) SetIndeterminate(v bool) {
+	if v && !w.indeterminate {
+		w.start = time.Time{}
+	}
+	w.indeterminate = v
+}
+
+func (w *ProgressBar) IsIndeterminate() bool { return w.indeterminate }
+
+func (w *ProgressBar) text() string {
+	if w.Format != nil {
+		return w.Format(w.value)
+	}
+	return fmt.Sprintf("%d%%", int(math.Round(w.value*100)))
+}
+
+func (w *ProgressBar) Update(ctx *uikit.Context) {
+	r := w.Measure(false)
+	if r.Dy() == 0 {
+		w.SetFrame(r.Min.X, r.Min.Y, r.Dx())
+	}
+
+	if w.start.IsZero() {
+		w.start = ctx.Now()
+	}
+}
+
+func (w *ProgressBar) Draw(ctx *uikit.Context, dst *ebiten.Image) {
+	r := w.Base.Draw(ctx, dst)
+	theme := ctx.Theme()
+	rtl := w.IsRTL(ctx)
+
+	radius := min(theme.Radius, r.Dy()/2)
+	fill := theme.FocusColor
+	if !w.IsEnabled() {
+		fill = theme.DisabledColor
+	}
+
+	w.DrawRoundedRect(dst, r, radius, theme.SurfacePressedColor)
+	w.DrawRoundedBorder(dst, r, radius, theme.BorderW, theme.BorderColor)
+
+	if w.indeterminate {
+		phase := 0.0
+		if !w.start.IsZero() {
+			phase = float64(ctx.Now().Sub(w.start)%progressSweep) / float64(progressSweep)
+		}
+
+		segW := r.Dx() / 3
+		x := r.Min.X - segW + int(phase*float64(r.Dx()+segW))
+		if rtl {
+			x = r.Max.X - (x - r.Min.X) - segW
+		}
+
+		// Clipped to the track so the segment enters and leaves smoothly.
+		seg := image.Rect(x, r.Min.Y, x+segW, r.Max.Y).Intersect(r)
+		if !seg.Empty() {
+			w.DrawRoundedRect(dst, seg, radius, fill)
+		}
+		return
+	}
+
+	fw := int(math.Round(w.value * float64(r.Dx())))
+	bar := image.Rect(r.Min.X, r.Min.Y, r.Min.X+fw, r.Max.Y)
+	if rtl {
+		bar = image.Rect(r.Max.X-fw, r.Min.Y, r.Max.X, r.Max.Y)
+	}
+	if fw > 0 {
+		w.DrawRoundedRect(dst, bar, min(radius, fw/2), fill)
+	}
+
+	if w.ShowText {
+		t := theme.Text()
+		t.SetColor(theme.TextColor)
+		t.SetAlign(etxt.Center)
+		t.Draw(dst, visualText(w.text(), rtl), r.Min.X+r.Dx()/2, r.Min.Y+r.Dy()/2)
+	}
+}
+
+// Spinner is an activity indicator: a ring of dots fading around a circle,
+// with an optional label on its trailing side.
+type Spinner struct {
+	uikit.Base
+
+	label   string
+	running bool
+	start   time.Time
+}
+
+func NewSpinner(theme *uikit.Theme, label string) *Spinner {
+	cfg := uikit.NewWidgetBaseConfig(theme)
+	cfg.DrawSurface = false
+	cfg.DrawBorder = false
+	cfg.DrawFocus = false
+
+	return &Spinner{
+		Base:    uikit.NewBase(cfg),
+		label:   label,
+		running: true,
+	}
+}
+
+func (w *Spinner) Focusable() bool { return false }
+
+func (w *Spinner) SetLabel(s string) { w.label = s }
+
+// SetRunning starts or stops the animation. Stopped spinners draw nothing.
+func (w *Spinner) SetRunning(v bool) {
+	if v && !w.running {
+		w.start = time.Time{}
+	}
+	w.running = v
+}
+
+func (w *Spinner) IsRunning() bool { return w.running }
+
+func (w *Spinner) Update(ctx *uikit.Context) {
+	r := w.Measure(false)
+	if r.Dy() == 0 {
+		w.SetFrame(r.Min.X, r.Min.Y, r.Dx())
+	}
+
+	if w.running && w.start.IsZero() {
+		w.start = ctx.Now()
+	}
+}
+
+func (w *Spinner) Draw(ctx *uikit.Context, dst *ebiten.Image) {
+	r := w.Base.Draw(ctx, dst)
+	if !w.running {
+		return
+	}
+
+	theme := ctx.Theme()
+	rtl := w.IsRTL(ctx)
+
+	size := theme.ControlH * 3 / 5
+	cx := r.Min.X + theme.PadX + size/2
+	if rtl {
+		cx = r.Max.X - theme.PadX - size/2
+	}
+	cy := r.Min.Y + r.Dy()/2
+
+	phase := 0.0
+	if !w.start.IsZero() {
+		phase = float64(ctx.Now().Sub(w.start)%spinnerTurn) / float64(spinnerTurn)
+	}
+	head := int(phase * spinnerDots)
+
+	dot := max(size/8, 2)
+	ring := float64(size/2 - dot)
+	col := theme.FocusColor
+	if !w.IsEnabled() {
+		col = theme.DisabledColor
+	}
+
+	for i := 0; i < spinnerDots; i++ {
+		// Dots fade out behind the head, clockwise.
+		age := (head - i + spinnerDots) % spinnerDots
+		a := 1 - float64(age)/spinnerDots
+
+		angle := 2*math.Pi*float64(i)/spinnerDots - math.Pi/2
+		x := cx + int(math.Round(ring*math.Cos(angle)))
+		y := cy + int(math.Round(ring*math.Sin(angle)))
+		w.DrawRoundedRect(dst, image.Rect(x-dot, y-dot, x+dot, y+dot), dot, fadeColor(col, a))
+	}
+
+	if w.label == "" {
+		return
+	}
+
+	t := theme.Text()
+	t.SetColor(theme.TextColor)
+	if rtl {
+		t.SetAlign(etxt.Right | etxt.VertCenter)
+		t.Draw(dst, visualText(w.label, rtl), cx-size/2-theme.SpaceS, cy)
+		return
+	}
+	t.SetAlign(etxt.Left | etxt.VertCenter)
+	t.Draw(dst, w.label, cx+size/2+theme.SpaceS, cy)
+}
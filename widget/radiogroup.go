@@ -0,0 +1,257 @@
+package widget
+
+import (
+	"image"
+
+	"github.com/erparts/go-uikit"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+var _ uikit.Widget = (*RadioGroup[int])(nil)
+
+// RadioOption is one choice of a RadioGroup.
+type RadioOption[T comparable] struct {
+	Value T
+	Label string
+}
+
+// RadioGroup is a set of mutually exclusive options drawn like Checkbox rows
+// (round boxes, wrapped labels).
+// - The group is a single Tab stop; arrows move the selection within it.
+// - Options are stacked vertically by default, or laid out in equal columns.
+// - EventValueChange carries the selected value in Event.Value.
+type RadioGroup[T comparable] struct {
+	uikit.Base
+
+	options     []RadioOption[T]
+	index       int // selected option, -1 if none
+	focus       int // option showing the focus ring
+	orientation Orientation
+
+	rows       []image.Rectangle // option rects relative to the widget origin
+	lastHeight int
+	refWidth   int
+	refRTL     bool
+}
+
+func NewRadioGroup[T comparable](theme *uikit.Theme, options []RadioOption[T]) *RadioGroup[T] {
+	cfg := uikit.NewWidgetBaseConfig(theme)
+	cfg.DrawSurface = false
+	cfg.DrawBorder = false
+	cfg.DrawFocus = false // drawn around the focused option instead
+
+	w := &RadioGroup[T]{
+		Base:     uikit.NewBase(cfg),
+		options:  options,
+		index:    -1,
+		refWidth: -1,
+	}
+	w.Base.HeightCalculator = w.heightCalculator
+	w.Base.On(uikit.EventClick, w.onClick, false)
+
+	return w
+}
+
+func (w *RadioGroup[T]) heightCalculator() int {
+	return max(w.lastHeight, w.Theme().ControlH)
+}
+
+func (w *RadioGroup[T]) Focusable() bool { return len(w.options) > 0 }
+
+func (w *RadioGroup[T]) SetOrientation(o Orientation) {
+	w.orientation = o
+	w.refWidth = -1
+}
+
+func (w *RadioGroup[T]) SetOptions(options []RadioOption[T]) {
+	w.options = options
+	w.refWidth = -1
+	if w.index >= len(options) {
+		w.index = -1
+	}
+	w.focus = clampInt(w.focus, 0, max(len(options)-1, 0))
+}
+
+func (w *RadioGroup[T]) Options() []RadioOption[T] { return w.options }
+
+// Index returns the selected option, or -1.
+func (w *RadioGroup[T]) Index() int { return w.index }
+
+// SetIndex selects an option; -1 clears the selection.
+func (w *RadioGroup[T]) SetIndex(i int) {
+	if i < -1 || i >= len(w.options) || i == w.index {
+		return
+	}
+
+	w.index = i
+	if i >= 0 {
+		w.focus = i
+	}
+
+	var v any
+	if i >= 0 {
+		v = w.options[i].Value
+	}
+	w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange, Value: v})
+}
+
+// Value returns the selected value.
+func (w *RadioGroup[T]) Value() (T, bool) {
+	if w.index < 0 {
+		var zero T
+		return zero, false
+	}
+	return w.options[w.index].Value, true
+}
+
+// SetValue selects the first option holding v. It reports whether one was found.
+func (w *RadioGroup[T]) SetValue(v T) bool {
+	for i, o := range w.options {
+		if o.Value == v {
+			w.SetIndex(i)
+			return true
+		}
+	}
+	return false
+}
+
+// layout computes the option rows for the given width.
+func (w *RadioGroup[T]) layout(theme *uikit.Theme, width int, rtl bool) {
+	w.rows = w.rows[:0]
+	w.lastHeight = 0
+	if len(w.options) == 0 {
+		return
+	}
+
+	if w.orientation == Horizontal {
+		gap := theme.SpaceS
+		colW := max((width-gap*(len(w.options)-1))/len(w.options), 0)
+		for _, o := range w.options {
+			w.lastHeight = max(w.lastHeight, checkRowHeight(theme, o.Label, colW, rtl))
+		}
+
+		for i := range w.options {
+			x := i * (colW + gap)
+			if rtl {
+				x = width - x - colW
+			}
+			w.rows = append(w.rows, image.Rect(x, 0, x+colW, w.lastHeight))
+		}
+		return
+	}
+
+	for _, o := range w.options {
+		h := checkRowHeight(theme, o.Label, width, rtl)
+		w.rows = append(w.rows, image.Rect(0, w.lastHeight, width, w.lastHeight+h))
+		w.lastHeight += h
+	}
+}
+
+// rowRect returns the screen rect of option i.
+func (w *RadioGroup[T]) rowRect(i int) image.Rectangle {
+	return w.rows[i].Add(w.Measure(false).Min)
+}
+
+func (w *RadioGroup[T]) optionAt(p image.Point) int {
+	for i := range w.rows {
+		if p.In(w.rowRect(i)) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (w *RadioGroup[T]) onClick(e uikit.Event) bool {
+	if !w.IsEnabled() || e.Pointer == nil {
+		return false
+	}
+
+	if i := w.optionAt(e.Pointer.Position); i >= 0 {
+		w.SetIndex(i)
+	}
+	return false
+}
+
+func (w *RadioGroup[T]) Update(ctx *uikit.Context) {
+	r := w.Measure(false)
+	rtl := w.IsRTL(ctx)
+	if w.refWidth != r.Dx() || w.refRTL != rtl || len(w.rows) != len(w.options) {
+		w.refWidth, w.refRTL = r.Dx(), rtl
+		w.layout(ctx.Theme(), r.Dx(), rtl)
+		w.SetFrame(r.Min.X, r.Min.Y, r.Dx())
+	}
+
+	if !w.IsEnabled() || !w.IsFocused() || len(w.options) == 0 {
+		return
+	}
+
+	prev, next := ebiten.KeyLeft, ebiten.KeyRight
+	if rtl {
+		prev, next = next, prev
+	}
+
+	// Selection follows the arrows, wrapping around. With nothing selected
+	// the first arrow selects the focused option.
+	n := len(w.options)
+	step := func(d int) {
+		if w.index < 0 {
+			w.SetIndex(w.focus)
+			return
+		}
+		w.SetIndex((w.focus + d + n) % n)
+	}
+	switch {
+	case keyRepeat(ebiten.KeyDown) || keyRepeat(next):
+		step(1)
+	case keyRepeat(ebiten.KeyUp) || keyRepeat(prev):
+		step(-1)
+	case inpututil.IsKeyJustPressed(ebiten.KeySpace):
+		w.SetIndex(w.focus)
+	}
+}
+
+func (w *RadioGroup[T]) Draw(ctx *uikit.Context, dst *ebiten.Image) {
+	w.Base.Draw(ctx, dst)
+	if len(w.rows) != len(w.options) {
+		return
+	}
+
+	theme := ctx.Theme()
+	rtl := w.IsRTL(ctx)
+	ptr := ctx.Pointer()
+
+	border, dotCol, textCol := theme.BorderColor, theme.FocusColor, theme.TextColor
+	if !w.IsEnabled() {
+		border, dotCol, textCol = theme.DisabledColor, theme.DisabledColor, theme.DisabledColor
+	}
+
+	for i, o := range w.options {
+		row := w.rowRect(i)
+		box := checkRowBox(theme, row, rtl)
+		size := box.Dx()
+
+		bg := theme.BackgroundColor
+		if w.IsEnabled() && w.IsHovered() && ptr.Position.In(row) {
+			bg = theme.BorderColor
+			if w.IsPressed() {
+				bg = theme.FocusColor
+			}
+		}
+
+		w.DrawRoundedRect(dst, box, size/2, bg)
+		w.DrawRoundedBorder(dst, box, size/2, theme.BorderW, border)
+
+		if i == w.index {
+			inset := max(size/4, 3)
+			dot := box.Inset(inset)
+			w.DrawRoundedRect(dst, dot, dot.Dx()/2, dotCol)
+		}
+
+		drawCheckLabel(theme, dst, o.Label, row, box, textCol, rtl)
+
+		if w.IsFocused() && w.IsEnabled() && i == w.focus {
+			w.DrawRoundedBorder(dst, row, theme.Radius, theme.FocusRingW, theme.FocusColor)
+		}
+	}
+}
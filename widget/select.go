@@ -6,12 +6,12 @@ import (
 
 	"github.com/erparts/go-uikit"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/tinne26/etxt"
 )
 
 type SelectOption struct {
 	Value any
 	Label string
+	Icon  *uikit.Icon // optional, drawn before the label
 }
 
 var _ uikit.Widget = (*Select)(nil)
@@ -243,23 +243,24 @@ func (s *Select) Draw(ctx *uikit.Context, dst *ebiten.Image) {
 
 	label := s.placeholder
 	col := theme.MutedTextColor
+	var icon *uikit.Icon
 
 	if val, ok := s.Selected(); ok {
-		label = val.Label
+		label, icon = val.Label, val.Icon
 		col = theme.TextColor
 	}
 
 	centerY := r.Min.Y + (r.Dy() / 2)
+	rtl := s.IsRTL(ctx)
 
-	t := theme.Text()
-	t.SetColor(col)
-	t.SetAlign(etxt.Left | etxt.VertCenter)
-	t.Draw(dst, label, r.Min.X+theme.PadX, centerY)
+	// Label on the start side, chevron on the end side (mirrored in RTL).
+	labelX, chevX := r.Min.X+theme.PadX, r.Max.X-theme.PadX
+	if rtl {
+		labelX, chevX = chevX, labelX
+	}
 
-	chev := "▾"
-	t.SetColor(theme.TextColor)
-	t.SetAlign(etxt.Right | etxt.VertCenter)
-	t.Draw(dst, chev, r.Max.X-theme.PadX, centerY)
+	drawIconText(theme, dst, icon, label, labelX, centerY, col, rtl)
+	drawDropChevron(theme, dst, chevX, centerY, rtl)
 }
 
 func (s *Select) DrawOverlay(ctx *uikit.Context, dst *ebiten.Image) {
@@ -295,9 +296,11 @@ func (s *Select) DrawOverlay(ctx *uikit.Context, dst *ebiten.Image) {
 		}
 
 		bY := row.Min.Y + row.Dy()/2
-		t := theme.Text()
-		t.SetColor(theme.TextColor)
-		t.SetAlign(etxt.Left | etxt.VertCenter)
-		t.Draw(dst, s.options[idx].Label, row.Min.X+theme.PadX, bY)
+		opt := s.options[idx]
+		if s.IsRTL(ctx) {
+			drawIconText(theme, dst, opt.Icon, opt.Label, row.Max.X-theme.PadX, bY, theme.TextColor, true)
+			continue
+		}
+		drawIconText(theme, dst, opt.Icon, opt.Label, row.Min.X+theme.PadX, bY, theme.TextColor, false)
 	}
 }
@@ -0,0 +1,165 @@
+package widget
+
+import (
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// SelectionMode controls how many items of a list-like widget can be
+// selected.
+type SelectionMode int
+
+const (
+	// SelectSingle selects the item under the cursor.
+	SelectSingle SelectionMode = iota
+	// SelectMulti adds Ctrl-click toggling and Shift range selection.
+	SelectMulti
+	// SelectNone only moves the cursor.
+	SelectNone
+)
+
+// listSelection is the cursor and selection state of index based widgets
+// (ListView, Table).
+type listSelection struct {
+	mode     SelectionMode
+	cursor   int // -1 when there is none
+	anchor   int // start of Shift range selection, -1 when unset
+	selected map[int]bool
+}
+
+func newListSelection() listSelection {
+	return listSelection{cursor: -1, anchor: -1, selected: make(map[int]bool)}
+}
+
+func (s *listSelection) setMode(m SelectionMode) {
+	s.mode = m
+	if m == SelectNone {
+		clear(s.selected)
+	}
+}
+
+// pick moves the cursor to i and updates the selection like a click with
+// the given modifiers.
+func (s *listSelection) pick(i int, toggle, extend bool) {
+	switch s.mode {
+	case SelectSingle:
+		clear(s.selected)
+		s.selected[i] = true
+	case SelectMulti:
+		switch {
+		case extend:
+			if !toggle {
+				clear(s.selected)
+			}
+			a := s.anchor
+			if a < 0 {
+				a = i
+			}
+			for j := min(a, i); j <= max(a, i); j++ {
+				s.selected[j] = true
+			}
+		case toggle:
+			if s.selected[i] {
+				delete(s.selected, i)
+			} else {
+				s.selected[i] = true
+			}
+			s.anchor = i
+		default:
+			clear(s.selected)
+			s.selected[i] = true
+			s.anchor = i
+		}
+	}
+	s.cursor = i
+}
+
+// set replaces the selection; single mode keeps the first index only.
+func (s *listSelection) set(indexes []int) {
+	clear(s.selected)
+	if s.mode == SelectNone {
+		return
+	}
+	for i, v := range indexes {
+		if i > 0 && s.mode == SelectSingle {
+			break
+		}
+		s.selected[v] = true
+	}
+}
+
+// indexes returns the selected indexes in ascending order.
+func (s *listSelection) indexes() []int {
+	out := make([]int, 0, len(s.selected))
+	for i := range s.selected {
+		out = append(out, i)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// clamp drops the indexes past the end of a list of n items.
+func (s *listSelection) clamp(n int) {
+	for i := range s.selected {
+		if i >= n {
+			delete(s.selected, i)
+		}
+	}
+	if s.cursor >= n {
+		s.cursor = n - 1
+	}
+	if s.anchor >= n {
+		s.anchor = -1
+	}
+}
+
+// handleKeys applies the navigation keys to a list of n items, page rows
+// per screen. It reports whether the cursor or selection changed and
+// whether Enter was pressed on the cursor.
+// - Up/Down, PageUp/PageDown, Home/End move the cursor; Shift extends a
+// multi selection and Ctrl moves the cursor alone.
+// - Space selects (toggles in multi mode) the cursor item.
+func (s *listSelection) handleKeys(n, page int) (changed, activate bool) {
+	if n == 0 {
+		return false, false
+	}
+
+	cur := s.cursor
+	target := -1
+	switch {
+	case keyRepeat(ebiten.KeyUp):
+		target = cur - 1
+	case keyRepeat(ebiten.KeyDown):
+		target = cur + 1
+	case keyRepeat(ebiten.KeyPageUp):
+		target = cur - page
+	case keyRepeat(ebiten.KeyPageDown):
+		target = cur + page
+	case inpututil.IsKeyJustPressed(ebiten.KeyHome):
+		target = 0
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnd):
+		target = n - 1
+	case cur < 0:
+		return false, false
+	case inpututil.IsKeyJustPressed(ebiten.KeySpace):
+		s.pick(cur, s.mode == SelectMulti, false)
+		return true, false
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyKPEnter):
+		return false, true
+	default:
+		return false, false
+	}
+
+	if cur < 0 {
+		target = 0
+	}
+	target = clampInt(target, 0, n-1)
+	if s.mode == SelectMulti && shortcutPressed() && !shiftPressed() {
+		s.cursor = target
+	} else {
+		s.pick(target, false, shiftPressed())
+	}
+	return true, false
+}
@@ -0,0 +1,399 @@
+package widget
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+
+	"github.com/erparts/go-uikit"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/tinne26/etxt"
+)
+
+var _ uikit.Widget = (*Slider)(nil)
+
+// Slider picks a number between Min and Max by dragging a thumb along a track.
+// The range variant has two thumbs selecting a [low, high] interval.
+// - Horizontal by default (min on the leading side); vertical sliders grow upwards.
+// - Arrows step, PageUp/PageDown move a tenth of the range, Home/End jump to the ends.
+// - Dragging captures the pointer, so the thumb follows it outside the widget.
+// - In range sliders the keyboard moves the thumb grabbed last.
+// Proportions derive from Theme.ControlH.
+type Slider struct {
+	uikit.Base
+
+	orientation Orientation
+	min, max    float64
+	step        float64
+
+	values [2]float64 // [value] or [low, high]
+	ranged bool
+	active int // thumb moved by the keyboard and the current drag
+
+	// TickStep draws a tick mark every TickStep units. Use 0 for none.
+	TickStep float64
+
+	// ShowValue draws the value (or range) next to the track.
+	ShowValue bool
+
+	// Format renders values for the label. Defaults to the shortest decimal form.
+	Format func(v float64) string
+
+	// Length is the height of vertical sliders. Defaults to 5 * ControlH.
+	Length int
+}
+
+func NewSlider(theme *uikit.Theme, min, max float64) *Slider {
+	cfg := uikit.NewWidgetBaseConfig(theme)
+	cfg.DrawSurface = false
+	cfg.DrawBorder = false
+
+	w := &Slider{
+		Base: uikit.NewBase(cfg),
+		min:  min,
+		max:  max,
+	}
+	w.values = [2]float64{min, max}
+	w.Base.HeightCalculator = w.calculateHeight
+
+	return w
+}
+
+// NewRangeSlider creates a two-thumb slider selecting an interval.
+func NewRangeSlider(theme *uikit.Theme, min, max float64) *Slider {
+	w := NewSlider(theme, min, max)
+	w.ranged = true
+	return w
+}
+
+func (w *Slider) calculateHeight() int {
+	if w.orientation == Vertical {
+		if w.Length > 0 {
+			return w.Length
+		}
+		return w.Theme().ControlH * 5
+	}
+	return w.Theme().ControlH
+}
+
+func (w *Slider) Focusable() bool { return true }
+
+func (w *Slider) SetOrientation(o Orientation) { w.orientation = o }
+func (w *Slider) Orientation() Orientation     { return w.orientation }
+
+// SetBounds changes Min and Max, clamping the current values.
+func (w *Slider) SetBounds(min, max float64) {
+	w.min, w.max = min, max
+	w.setValues(w.values[0], w.values[1])
+}
+
+func (w *Slider) Min() float64 { return w.min }
+func (w *Slider) Max() float64 { return w.max }
+
+// SetStep sets the value granularity. Use 0 for continuous values.
+func (w *Slider) SetStep(step float64) {
+	w.step = max(step, 0)
+	w.setValues(w.values[0], w.values[1])
+}
+
+func (w *Slider) Step() float64 { return w.step }
+
+// Value returns the value of a single-thumb slider (the low end of a range).
+func (w *Slider) Value() float64 { return w.values[0] }
+
+func (w *Slider) SetValue(v float64) {
+	w.setValues(v, w.values[1])
+}
+
+// Range returns the interval of a range slider.
+func (w *Slider) Range() (low, high float64) { return w.values[0], w.values[1] }
+
+func (w *Slider) SetRange(low, high float64) {
+	w.setValues(low, high)
+}
+
+func (w *Slider) IsRange() bool { return w.ranged }
+
+// snap clamps v into [min, max] and rounds it to the step.
+func (w *Slider) snap(v float64) float64 {
+	lo, hi := min(w.min, w.max), max(w.min, w.max)
+	if w.step > 0 {
+		v = w.min + math.Round((v-w.min)/w.step)*w.step
+	}
+	return math.Max(lo, math.Min(hi, v))
+}
+
+func (w *Slider) setValues(low, high float64) {
+	low, high = w.snap(low), w.snap(high)
+	if w.ranged && low > high {
+		low, high = high, low
+	}
+
+	if low == w.values[0] && high == w.values[1] {
+		return
+	}
+	w.values = [2]float64{low, high}
+	w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange})
+}
+
+// moveThumb sets the value of thumb i, without crossing the other thumb.
+func (w *Slider) moveThumb(i int, v float64) {
+	v = w.snap(v)
+	if !w.ranged {
+		w.setValues(v, w.values[1])
+		return
+	}
+
+	if i == 0 {
+		w.setValues(math.Min(v, w.values[1]), w.values[1])
+	} else {
+		w.setValues(w.values[0], math.Max(v, w.values[0]))
+	}
+}
+
+func (w *Slider) format(v float64) string {
+	if w.Format != nil {
+		return w.Format(v)
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func (w *Slider) label() string {
+	if w.ranged {
+		return w.format(w.values[0]) + " – " + w.format(w.values[1])
+	}
+	return w.format(w.values[0])
+}
+
+// metrics returns the thumb radius and the track thickness.
+func (w *Slider) metrics(theme *uikit.Theme) (radius, thick int) {
+	return max(theme.ControlH/4, 4), max(theme.ControlH/10, 2)
+}
+
+// track returns the segment the thumb centres move along: from (x0, y0) for
+// Min to (x1, y1) for Max.
+func (w *Slider) track(ctx *uikit.Context) (p0, p1 image.Point) {
+	theme := ctx.Theme()
+	r := w.Measure(false)
+	radius, _ := w.metrics(theme)
+
+	if w.orientation == Vertical {
+		labelH := 0
+		if w.ShowValue {
+			labelH = theme.Text().Measure(" ").IntHeight() + theme.SpaceS
+		}
+		x := r.Min.X + r.Dx()/2
+		return image.Pt(x, r.Max.Y-labelH-radius-theme.SpaceS), image.Pt(x, r.Min.Y+radius+theme.SpaceS)
+	}
+
+	labelW := 0
+	if w.ShowValue {
+		widest := w.format(w.max)
+		if w.ranged {
+			widest = widest + " – " + widest
+		}
+		labelW = theme.Text().Measure(widest).IntWidth() + theme.SpaceM
+	}
+
+	y := r.Min.Y + r.Dy()/2
+	x0, x1 := r.Min.X+theme.PadX+radius, r.Max.X-theme.PadX-radius-labelW
+	if w.IsRTL(ctx) {
+		x0, x1 = r.Max.X-theme.PadX-radius, r.Min.X+theme.PadX+radius+labelW
+	}
+	return image.Pt(x0, y), image.Pt(x1, y)
+}
+
+// fraction maps v to [0, 1] along the track.
+func (w *Slider) fraction(v float64) float64 {
+	if w.max == w.min {
+		return 0
+	}
+	return (v - w.min) / (w.max - w.min)
+}
+
+func (w *Slider) pointAt(p0, p1 image.Point, v float64) image.Point {
+	f := w.fraction(v)
+	return image.Pt(p0.X+int(math.Round(f*float64(p1.X-p0.X))), p0.Y+int(math.Round(f*float64(p1.Y-p0.Y))))
+}
+
+// valueAt projects a pointer position on the track.
+func (w *Slider) valueAt(p0, p1, pos image.Point) float64 {
+	var f float64
+	if w.orientation == Vertical {
+		if p1.Y != p0.Y {
+			f = float64(pos.Y-p0.Y) / float64(p1.Y-p0.Y)
+		}
+	} else if p1.X != p0.X {
+		f = float64(pos.X-p0.X) / float64(p1.X-p0.X)
+	}
+
+	f = math.Max(0, math.Min(1, f))
+	return w.min + f*(w.max-w.min)
+}
+
+// keyStep returns the amount moved by the arrow keys.
+func (w *Slider) keyStep() float64 {
+	if w.step > 0 {
+		return w.step
+	}
+	return (w.max - w.min) / 100
+}
+
+func (w *Slider) Update(ctx *uikit.Context) {
+	r := w.Measure(false)
+	if r.Dy() == 0 {
+		w.SetFrame(r.Min.X, r.Min.Y, r.Dx())
+	}
+
+	if !w.IsEnabled() {
+		return
+	}
+
+	ptr := ctx.Pointer()
+	p0, p1 := w.track(ctx)
+
+	if ptr.IsJustDown && ptr.Position.In(r) {
+		v := w.valueAt(p0, p1, ptr.Position)
+
+		// The range thumb closest to the press is grabbed.
+		w.active = 0
+		if w.ranged {
+			d0, d1 := math.Abs(v-w.values[0]), math.Abs(v-w.values[1])
+			if d1 < d0 || (d1 == d0 && v > w.values[1]) {
+				w.active = 1
+			}
+		}
+
+		ctx.CapturePointer(w)
+		w.moveThumb(w.active, v)
+	} else if ctx.PointerCapture() == w && (ptr.IsDown || ptr.IsJustUp) {
+		w.moveThumb(w.active, w.valueAt(p0, p1, ptr.Position))
+	}
+
+	if !w.IsFocused() {
+		return
+	}
+
+	dec, inc := ebiten.KeyLeft, ebiten.KeyRight
+	if w.orientation == Horizontal && w.IsRTL(ctx) {
+		dec, inc = inc, dec
+	}
+
+	v := w.values[w.active]
+	page := (w.max - w.min) / 10
+	switch {
+	case keyRepeat(inc) || keyRepeat(ebiten.KeyUp):
+		w.moveThumb(w.active, v+w.keyStep())
+	case keyRepeat(dec) || keyRepeat(ebiten.KeyDown):
+		w.moveThumb(w.active, v-w.keyStep())
+	case keyRepeat(ebiten.KeyPageUp):
+		w.moveThumb(w.active, v+page)
+	case keyRepeat(ebiten.KeyPageDown):
+		w.moveThumb(w.active, v-page)
+	case keyRepeat(ebiten.KeyHome):
+		w.moveThumb(w.active, w.min)
+	case keyRepeat(ebiten.KeyEnd):
+		w.moveThumb(w.active, w.max)
+	}
+}
+
+func (w *Slider) Draw(ctx *uikit.Context, dst *ebiten.Image) {
+	w.Base.Draw(ctx, dst)
+
+	theme := ctx.Theme()
+	r := w.Measure(false)
+	radius, thick := w.metrics(theme)
+	p0, p1 := w.track(ctx)
+
+	trackCol, fillCol, thumbBorder := theme.BorderColor, theme.FocusColor, theme.FocusColor
+	if !w.IsEnabled() {
+		fillCol, thumbBorder = theme.DisabledColor, theme.DisabledColor
+	}
+
+	segment := func(a, b image.Point, col color.RGBA) {
+		rect := image.Rectangle{Min: a, Max: b}.Canon()
+		if w.orientation == Vertical {
+			rect.Min.X, rect.Max.X = a.X-thick/2, a.X-thick/2+thick
+		} else {
+			rect.Min.Y, rect.Max.Y = a.Y-thick/2, a.Y-thick/2+thick
+		}
+		w.DrawRoundedRect(dst, rect, thick/2, col)
+	}
+
+	// Ticks sit on one side of the track. Values are computed from the tick
+	// index so the error doesn't build up, and when ticks would be less than
+	// a pixel apart only every stride-th one is drawn.
+	if w.TickStep > 0 && w.max > w.min {
+		tickL := max(thick*2, 3)
+		tickW := theme.BorderW
+		span := w.max - w.min
+		n := int(math.Floor(span/w.TickStep + 0.5))
+
+		stride := n + 1
+		if length := float64(max(p1.X-p0.X, p1.Y-p0.Y, p0.X-p1.X, p0.Y-p1.Y)); length > 0 {
+			gap := length * w.TickStep / span
+			stride = max(int(math.Ceil(float64(tickW+1)/gap)), 1)
+		}
+
+		for k := 0; k <= n; k += stride {
+			p := w.pointAt(p0, p1, math.Min(w.min+float64(k)*w.TickStep, w.max))
+			tick := image.Rect(p.X-tickW/2, p.Y+radius, p.X-tickW/2+tickW, p.Y+radius+tickL)
+			if w.orientation == Vertical {
+				tick = image.Rect(p.X+radius, p.Y-tickW/2, p.X+radius+tickL, p.Y-tickW/2+tickW)
+			}
+			w.DrawRoundedRect(dst, tick, 0, theme.BorderColor)
+		}
+	}
+
+	segment(p0, p1, trackCol)
+	if w.ranged {
+		segment(w.pointAt(p0, p1, w.values[0]), w.pointAt(p0, p1, w.values[1]), fillCol)
+	} else {
+		segment(p0, w.pointAt(p0, p1, w.values[0]), fillCol)
+	}
+
+	thumbs := 1
+	if w.ranged {
+		thumbs = 2
+	}
+	for i := 0; i < thumbs; i++ {
+		c := w.pointAt(p0, p1, w.values[i])
+		rr := radius
+		if ctx.PointerCapture() == w && i == w.active {
+			rr += max(radius/4, 1)
+		}
+		thumb := image.Rect(c.X-rr, c.Y-rr, c.X+rr, c.Y+rr)
+		w.DrawRoundedRect(dst, thumb, rr, theme.SurfaceColor)
+
+		bw := theme.BorderW
+		if w.IsFocused() && i == w.active {
+			bw = theme.FocusRingW
+		}
+		w.DrawRoundedBorder(dst, thumb, rr, max(bw, 1), thumbBorder)
+	}
+
+	if !w.ShowValue {
+		return
+	}
+
+	col := theme.TextColor
+	if !w.IsEnabled() {
+		col = theme.DisabledColor
+	}
+
+	t := theme.Text()
+	t.SetColor(col)
+	label := visualText(w.label(), w.IsRTL(ctx))
+	switch {
+	case w.orientation == Vertical:
+		t.SetAlign(etxt.HorzCenter | etxt.Bottom)
+		t.Draw(dst, label, r.Min.X+r.Dx()/2, r.Max.Y-theme.SpaceS)
+	case w.IsRTL(ctx):
+		t.SetAlign(etxt.Left | etxt.VertCenter)
+		t.Draw(dst, label, r.Min.X+theme.PadX, r.Min.Y+r.Dy()/2)
+	default:
+		t.SetAlign(etxt.Right | etxt.VertCenter)
+		t.Draw(dst, label, r.Max.X-theme.PadX, r.Min.Y+r.Dy()/2)
+	}
+}
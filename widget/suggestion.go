@@ -0,0 +1,187 @@
+package widget
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Suggestion is a single entry offered by a SuggestionProvider.
+type Suggestion struct {
+	Value any
+	Label string
+
+	// Match holds the [start, end) byte ranges of Label matching the query,
+	// in increasing order. They are highlighted when drawn.
+	Match [][2]int
+}
+
+// SuggestionProvider produces suggestions for a query.
+// done may be called synchronously or later from any goroutine; results for
+// outdated queries are discarded by the caller.
+type SuggestionProvider interface {
+	Suggest(query string, done func([]Suggestion))
+}
+
+// SuggestionFunc adapts a function to the SuggestionProvider interface.
+type SuggestionFunc func(query string, done func([]Suggestion))
+
+func (f SuggestionFunc) Suggest(query string, done func([]Suggestion)) {
+	f(query, done)
+}
+
+// StaticSuggestions offers every option whose label contains the query
+// (case-insensitive), keeping the original order.
+func StaticSuggestions(options []SelectOption) SuggestionProvider {
+	return SuggestionFunc(func(query string, done func([]Suggestion)) {
+		var out []Suggestion
+		for _, o := range options {
+			if start, end, ok := indexFold(o.Label, query); ok {
+				out = append(out, newSuggestion(o, [2]int{start, end}))
+			}
+		}
+
+		done(out)
+	})
+}
+
+// PrefixSuggestions offers every option whose label starts with the query
+// (case-insensitive), keeping the original order.
+func PrefixSuggestions(options []SelectOption) SuggestionProvider {
+	return SuggestionFunc(func(query string, done func([]Suggestion)) {
+		var out []Suggestion
+		for _, o := range options {
+			if start, end, ok := indexFold(o.Label, query); ok && start == 0 {
+				out = append(out, newSuggestion(o, [2]int{start, end}))
+			}
+		}
+
+		done(out)
+	})
+}
+
+// FuzzySuggestions offers every option containing the query characters in
+// order (case-insensitive), best matches first. Consecutive characters and
+// characters at word starts score higher.
+func FuzzySuggestions(options []SelectOption) SuggestionProvider {
+	return SuggestionFunc(func(query string, done func([]Suggestion)) {
+		type scored struct {
+			s     Suggestion
+			score int
+		}
+
+		var res []scored
+		for _, o := range options {
+			if score, match, ok := fuzzyMatch(o.Label, query); ok {
+				res = append(res, scored{s: newSuggestion(o, match...), score: score})
+			}
+		}
+
+		sort.SliceStable(res, func(i, j int) bool { return res[i].score > res[j].score })
+
+		out := make([]Suggestion, len(res))
+		for i, r := range res {
+			out[i] = r.s
+		}
+
+		done(out)
+	})
+}
+
+// AsyncSuggestions runs fn on its own goroutine for every query, for
+// providers backed by slow sources (network, disk, large indexes).
+func AsyncSuggestions(fn func(query string) []Suggestion) SuggestionProvider {
+	return SuggestionFunc(func(query string, done func([]Suggestion)) {
+		go func() {
+			done(fn(query))
+		}()
+	})
+}
+
+func newSuggestion(o SelectOption, match ...[2]int) Suggestion {
+	s := Suggestion{Value: o.Value, Label: o.Label}
+	for _, m := range match {
+		if m[0] < m[1] {
+			s.Match = append(s.Match, m)
+		}
+	}
+
+	return s
+}
+
+// indexFold returns the byte range of the first case-insensitive occurrence of
+// substr in s.
+func indexFold(s, substr string) (start, end int, ok bool) {
+	if substr == "" {
+		return 0, 0, true
+	}
+
+	for i := range s {
+		if n, ok := hasPrefixFold(s[i:], substr); ok {
+			return i, i + n, true
+		}
+	}
+
+	return 0, 0, false
+}
+
+// hasPrefixFold reports whether s starts with prefix (case-insensitive) and
+// how many bytes of s the prefix covers.
+func hasPrefixFold(s, prefix string) (int, bool) {
+	n := 0
+	for _, pr := range prefix {
+		r, sz := utf8.DecodeRuneInString(s[n:])
+		if sz == 0 || unicode.ToLower(r) != unicode.ToLower(pr) {
+			return 0, false
+		}
+		n += sz
+	}
+
+	return n, true
+}
+
+func fuzzyMatch(s, query string) (score int, match [][2]int, ok bool) {
+	if query == "" {
+		return 0, nil, true
+	}
+
+	q := []rune(strings.ToLower(query))
+	qi := 0
+	prevMatched := false
+	prevRune := ' '
+
+	for i, r := range s {
+		if qi == len(q) {
+			break
+		}
+
+		if unicode.ToLower(r) != q[qi] {
+			prevMatched = false
+			prevRune = r
+			continue
+		}
+
+		score++
+		end := i + utf8.RuneLen(r)
+		if prevMatched {
+			score += 2
+			match[len(match)-1][1] = end
+		} else {
+			match = append(match, [2]int{i, end})
+		}
+		if !unicode.IsLetter(prevRune) && !unicode.IsDigit(prevRune) {
+			score += 3
+		}
+
+		qi++
+		prevMatched = true
+		prevRune = r
+	}
+
+	if qi < len(q) {
+		return 0, nil, false
+	}
+
+	return score, match, true
+}
@@ -0,0 +1,194 @@
+package widget
+
+import (
+	"image"
+	"time"
+
+	"github.com/erparts/go-uikit"
+	"github.com/erparts/go-uikit/common"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/tinne26/etxt"
+)
+
+var _ uikit.Widget = (*Switch)(nil)
+
+// Switch is an on/off control with a sliding knob, for settings screens.
+// - Clicking anywhere on the widget or pressing Space toggles it.
+// - The knob slides over Theme.AnimDuration.
+// - The label follows the switch, or precedes it with LabelLeading (the
+// switch then sits on the trailing edge).
+type Switch struct {
+	uikit.Base
+
+	label string
+	on    bool
+
+	// LabelLeading draws the label before the switch.
+	LabelLeading bool
+
+	knob     float64 // animated knob position, 0 (off) to 1 (on)
+	lastTick time.Time
+
+	lastHeight int
+	refWidth   int
+}
+
+func NewSwitch(theme *uikit.Theme, label string) *Switch {
+	cfg := uikit.NewWidgetBaseConfig(theme)
+	cfg.DrawSurface = false
+	cfg.DrawBorder = false
+
+	w := &Switch{
+		Base:     uikit.NewBase(cfg),
+		label:    label,
+		refWidth: -1,
+	}
+	w.Base.HeightCalculator = w.heightCalculator
+	w.Base.On(uikit.EventClick, w.onClick, false)
+
+	return w
+}
+
+func (w *Switch) heightCalculator() int {
+	return max(w.lastHeight, w.Theme().ControlH)
+}
+
+func (w *Switch) Focusable() bool { return true }
+
+func (w *Switch) SetLabel(s string) {
+	w.label = s
+	w.refWidth = -1
+}
+
+func (w *Switch) IsOn() bool { return w.on }
+
+// SetOn changes the state; the knob animates to the new position.
+func (w *Switch) SetOn(v bool) {
+	if w.on == v {
+		return
+	}
+	w.on = v
+	w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange, Value: v})
+}
+
+func (w *Switch) onClick(e uikit.Event) bool {
+	if w.IsEnabled() {
+		w.SetOn(!w.on)
+	}
+	return false
+}
+
+// trackSize returns the track dimensions, derived from the control height.
+func (w *Switch) trackSize(theme *uikit.Theme) (width, height int) {
+	h := max(theme.ControlH/2, 12)
+	return h * 7 / 4, h
+}
+
+func (w *Switch) labelWidth(theme *uikit.Theme, width int) int {
+	tw, _ := w.trackSize(theme)
+	return max(width-tw-theme.SpaceS-theme.PadX*2, 0)
+}
+
+// track returns the track rect, mirrored in RTL.
+func (w *Switch) track(theme *uikit.Theme, r image.Rectangle, rtl bool) image.Rectangle {
+	tw, th := w.trackSize(theme)
+	content := common.Inset(r, theme.PadX, theme.PadY)
+	y := r.Min.Y + (r.Dy()-th)/2
+
+	// The switch is on the leading side unless the label leads.
+	x := content.Min.X
+	if rtl != w.LabelLeading {
+		x = content.Max.X - tw
+	}
+	return image.Rect(x, y, x+tw, y+th)
+}
+
+func (w *Switch) Update(ctx *uikit.Context) {
+	r := w.Measure(false)
+	if w.refWidth != r.Dx() {
+		w.refWidth = r.Dx()
+		theme := ctx.Theme()
+		w.lastHeight = wrappedHeight(theme.Text(), w.label, w.labelWidth(theme, r.Dx()), w.IsRTL(ctx)) + theme.PadY*2
+		w.SetFrame(r.Min.X, r.Min.Y, r.Dx())
+	}
+
+	now := ctx.Now()
+	target := 0.0
+	if w.on {
+		target = 1
+	}
+	if w.lastTick.IsZero() {
+		w.knob = target
+	}
+	w.knob = approach(w.knob, target, now.Sub(w.lastTick), ctx.Theme().AnimDuration)
+	w.lastTick = now
+
+	if !w.IsEnabled() {
+		return
+	}
+
+	if w.IsFocused() && inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		w.SetOn(!w.on)
+	}
+}
+
+func (w *Switch) Draw(ctx *uikit.Context, dst *ebiten.Image) {
+	w.Base.Draw(ctx, dst)
+
+	theme := ctx.Theme()
+	r := w.Measure(false)
+	rtl := w.IsRTL(ctx)
+	track := w.track(theme, r, rtl)
+
+	trackCol := lerpColor(theme.BorderColor, theme.FocusColor, w.knob)
+	knobCol := theme.TextColor
+	textCol := theme.TextColor
+	if !w.IsEnabled() {
+		trackCol = lerpColor(theme.SurfacePressedColor, theme.DisabledColor, w.knob)
+		knobCol, textCol = theme.DisabledColor, theme.DisabledColor
+	} else if w.IsHovered() || w.IsPressed() {
+		trackCol = lerpColor(trackCol, theme.TextColor, 0.12)
+	}
+
+	th := track.Dy()
+	w.DrawRoundedRect(dst, track, th/2, trackCol)
+
+	// The "on" side is the trailing one.
+	inset := max(th/8, 2)
+	d := th - inset*2
+	f := w.knob
+	if rtl {
+		f = 1 - f
+	}
+	kx := track.Min.X + inset + int(f*float64(track.Dx()-inset*2-d))
+	knob := image.Rect(kx, track.Min.Y+inset, kx+d, track.Min.Y+inset+d)
+	w.DrawRoundedRect(dst, knob, d/2, knobCol)
+
+	t := theme.Text()
+	t.SetColor(textCol)
+	maxW := w.labelWidth(theme, r.Dx())
+	y := r.Min.Y + r.Dy()/2
+
+	// The label sits on the other side of the track.
+	if track.Min.X-r.Min.X > r.Max.X-track.Max.X {
+		x := r.Min.X + theme.PadX
+		if rtl {
+			t.SetAlign(etxt.Right | etxt.VertCenter)
+			x = track.Min.X - theme.SpaceS
+		} else {
+			t.SetAlign(etxt.Left | etxt.VertCenter)
+		}
+		drawWrapped(t, dst, w.label, x, y, maxW, rtl)
+		return
+	}
+
+	x := r.Max.X - theme.PadX
+	if rtl {
+		t.SetAlign(etxt.Right | etxt.VertCenter)
+	} else {
+		t.SetAlign(etxt.Left | etxt.VertCenter)
+		x = track.Max.X + theme.SpaceS
+	}
+	drawWrapped(t, dst, w.label, x, y, maxW, rtl)
+}
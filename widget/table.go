@@ -0,0 +1,779 @@
+package widget
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/erparts/go-uikit"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/tinne26/etxt"
+)
+
+var _ uikit.Widget = (*Table[struct{}])(nil)
+
+// TableColumn describes one column of a Table of R rows.
+type TableColumn[R any] struct {
+	Title string
+
+	// Width in pixels; 0 fits the title. Dragging the header edge changes it.
+	Width    int
+	MinWidth int
+
+	// AlignEnd puts the cell text on the trailing side (e.g. numbers).
+	AlignEnd bool
+
+	// Text renders the cell of a row.
+	Text func(row R) string
+
+	// Less orders rows when sorting by this column; nil makes the column
+	// unsortable.
+	Less func(a, b R) bool
+}
+
+// TextColumn shows text and sorts it case-insensitively.
+func TextColumn[R any](title string, text func(R) string) *TableColumn[R] {
+	return &TableColumn[R]{
+		Title: title,
+		Text:  text,
+		Less: func(a, b R) bool {
+			return strings.ToLower(text(a)) < strings.ToLower(text(b))
+		},
+	}
+}
+
+// NumberColumn shows value with a fmt format (e.g. "%.2f"), aligned to the
+// trailing edge and sorted numerically.
+func NumberColumn[R any](title, format string, value func(R) float64) *TableColumn[R] {
+	return &TableColumn[R]{
+		Title:    title,
+		AlignEnd: true,
+		Text:     func(row R) string { return fmt.Sprintf(format, value(row)) },
+		Less:     func(a, b R) bool { return value(a) < value(b) },
+	}
+}
+
+// TimeColumn shows value with a time layout and sorts chronologically.
+func TimeColumn[R any](title, layout string, value func(R) time.Time) *TableColumn[R] {
+	return &TableColumn[R]{
+		Title: title,
+		Text:  func(row R) string { return value(row).Format(layout) },
+		Less:  func(a, b R) bool { return value(a).Before(value(b)) },
+	}
+}
+
+// tableDrag is a header press: a click (sort), a column move or a resize.
+type tableDrag int
+
+const (
+	tableDragNone tableDrag = iota
+	tableDragPress
+	tableDragMove
+	tableDragResize
+)
+
+// Table shows rows of R in columns under a header.
+// - Clicking a sortable header sorts by it, clicking again reverses the
+// order. Sorting is stable: equal rows keep the order they were set in.
+// - Dragging a header edge resizes the column, dragging the header moves
+// it. With FreezeFirst the first column stays in place while the others
+// scroll horizontally.
+// - Only the rows and columns in view are drawn, so large tables stay
+// cheap. With SetHeight the rows scroll vertically; the columns scroll with
+// the horizontal wheel, Shift+wheel or Left/Right.
+// - Rows are selected like in a ListView (see SelectionMode); Enter calls
+// OnActivate.
+// - Row indexes in the API are indexes into the slice given to SetRows,
+// whatever the sort order. EventValueChange carries the cursor row.
+type Table[R any] struct {
+	uikit.Base
+
+	columns []*TableColumn[R] // display order
+	rows    []R
+	order   []int // view position -> row index
+
+	sortCol  *TableColumn[R]
+	sortDesc bool
+
+	// FreezeFirst keeps the first column in view while scrolling sideways.
+	FreezeFirst bool
+
+	// OnActivate is called when Enter is pressed on a row.
+	OnActivate func(row int)
+
+	sel     listSelection // over view positions
+	height  int
+	scroll  uikit.Scroller
+	scrollX int
+	colX    []int // offsets of the columns from the first scrolling one
+	reveal  bool
+	rtl     bool
+
+	drag      tableDrag
+	dragCol   *TableColumn[R]
+	dragX     int // logical x of the press
+	dragWidth int // column width at the press
+}
+
+func NewTable[R any](theme *uikit.Theme) *Table[R] {
+	cfg := uikit.NewWidgetBaseConfig(theme)
+	cfg.DrawSurface = false // drawn over the visible part only
+	cfg.DrawBorder = false
+	cfg.DrawFocus = false // drawn around the cursor row instead
+
+	w := &Table[R]{
+		Base:   uikit.NewBase(cfg),
+		sel:    newListSelection(),
+		scroll: uikit.NewScroller(),
+	}
+	w.Base.HeightCalculator = w.heightCalculator
+	w.Base.On(uikit.EventPointerDown, w.onPointerDown, false)
+
+	return w
+}
+
+func (w *Table[R]) heightCalculator() int {
+	if w.height > 0 {
+		return w.height
+	}
+	return w.rowH() * (len(w.rows) + 1)
+}
+
+func (w *Table[R]) Focusable() bool { return true }
+
+func (w *Table[R]) handlesEnter() bool { return w.OnActivate != nil }
+
+// SetHeight fixes the table height (header included) and makes the rows
+// scroll. Use 0 to show every row.
+func (w *Table[R]) SetHeight(h int) {
+	w.height = h
+}
+
+// AddColumn appends a column on the trailing side.
+func (w *Table[R]) AddColumn(c *TableColumn[R]) *TableColumn[R] {
+	w.columns = append(w.columns, c)
+	return c
+}
+
+// Columns returns the columns in display order.
+func (w *Table[R]) Columns() []*TableColumn[R] { return w.columns }
+
+// MoveColumn moves the column at display index from to index to.
+func (w *Table[R]) MoveColumn(from, to int) {
+	n := len(w.columns)
+	if from < 0 || from >= n || to < 0 || to >= n || from == to {
+		return
+	}
+
+	c := w.columns[from]
+	w.columns = append(w.columns[:from], w.columns[from+1:]...)
+	w.columns = append(w.columns[:to], append([]*TableColumn[R]{c}, w.columns[to:]...)...)
+	w.layoutColumns()
+}
+
+// SetRows replaces the rows, keeping the sort column. The selection is
+// cleared.
+func (w *Table[R]) SetRows(rows []R) {
+	w.rows = rows
+	clear(w.sel.selected)
+	w.sel.cursor, w.sel.anchor = -1, -1
+
+	w.order = w.order[:0]
+	for i := range rows {
+		w.order = append(w.order, i)
+	}
+	w.sortRows()
+}
+
+func (w *Table[R]) Rows() []R { return w.rows }
+
+// Refresh sorts the rows again after they were modified in place. If rows
+// were added or removed, it behaves like SetRows.
+func (w *Table[R]) Refresh() {
+	if len(w.order) != len(w.rows) {
+		w.SetRows(w.rows)
+		return
+	}
+	w.resort()
+}
+
+// SortBy sorts the rows by c, descending if desc. A nil column restores the
+// order given to SetRows.
+func (w *Table[R]) SortBy(c *TableColumn[R], desc bool) {
+	if c != nil && c.Less == nil {
+		return
+	}
+	w.sortCol, w.sortDesc = c, desc
+	w.resort()
+}
+
+// SortColumn returns the sort column (nil if unsorted) and direction.
+func (w *Table[R]) SortColumn() (*TableColumn[R], bool) { return w.sortCol, w.sortDesc }
+
+// resort sorts the rows keeping the selection and cursor on the same rows.
+func (w *Table[R]) resort() {
+	selected := make([]int, 0, len(w.sel.selected))
+	for p := range w.sel.selected {
+		selected = append(selected, w.order[p])
+	}
+	cursor, anchor := w.rowAtPos(w.sel.cursor), w.rowAtPos(w.sel.anchor)
+
+	for i := range w.order {
+		w.order[i] = i
+	}
+	w.sortRows()
+
+	pos := make([]int, len(w.order))
+	for p, i := range w.order {
+		pos[i] = p
+	}
+
+	clear(w.sel.selected)
+	for _, i := range selected {
+		w.sel.selected[pos[i]] = true
+	}
+	w.sel.cursor, w.sel.anchor = -1, -1
+	if cursor >= 0 {
+		w.sel.cursor = pos[cursor]
+		w.reveal = true
+	}
+	if anchor >= 0 {
+		w.sel.anchor = pos[anchor]
+	}
+}
+
+// sortRows sorts order, which must hold the row indexes ascending.
+func (w *Table[R]) sortRows() {
+	c := w.sortCol
+	if c == nil || c.Less == nil {
+		return
+	}
+
+	sort.SliceStable(w.order, func(i, j int) bool {
+		a, b := w.rows[w.order[i]], w.rows[w.order[j]]
+		if w.sortDesc {
+			return c.Less(b, a)
+		}
+		return c.Less(a, b)
+	})
+}
+
+func (w *Table[R]) rowAtPos(p int) int {
+	if p < 0 || p >= len(w.order) {
+		return -1
+	}
+	return w.order[p]
+}
+
+func (w *Table[R]) SetSelectionMode(m SelectionMode) { w.sel.setMode(m) }
+
+// Selected returns the selected rows in display order.
+func (w *Table[R]) Selected() []int {
+	out := w.sel.indexes()
+	for k, p := range out {
+		out[k] = w.order[p]
+	}
+	return out
+}
+
+func (w *Table[R]) IsSelected(row int) bool {
+	for p := range w.sel.selected {
+		if w.order[p] == row {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Table[R]) ClearSelection() { clear(w.sel.selected) }
+
+// Cursor returns the row under the keyboard cursor, or -1.
+func (w *Table[R]) Cursor() int { return w.rowAtPos(w.sel.cursor) }
+
+func (w *Table[R]) rowH() int { return w.Theme().ControlH }
+
+func (w *Table[R]) columnWidth(c *TableColumn[R]) int {
+	theme := w.Theme()
+	if c.Width <= 0 {
+		c.Width = max(theme.Text().Measure(c.Title).IntWidth()+theme.PadX*2+theme.ControlH/2, theme.ControlH*3)
+	}
+	return max(c.Width, c.MinWidth, theme.ControlH)
+}
+
+func (w *Table[R]) frozen() int {
+	if w.FreezeFirst && len(w.columns) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// areas returns the header and the rows viewport on screen.
+func (w *Table[R]) areas() (header, body image.Rectangle) {
+	r := w.Measure(false)
+	h := w.rowH()
+	header = image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+h)
+	body = image.Rect(r.Min.X, r.Min.Y+h, r.Max.X, max(r.Max.Y, r.Min.Y+h))
+	return header, body
+}
+
+// frozenWidth returns the width of the frozen column, 0 if none.
+func (w *Table[R]) frozenWidth() int {
+	if w.frozen() == 0 {
+		return 0
+	}
+	return w.columnWidth(w.columns[0])
+}
+
+// contentWidth returns the width of the scrolling columns.
+func (w *Table[R]) contentWidth() int {
+	if len(w.colX) != len(w.columns)+1 {
+		w.layoutColumns()
+	}
+	return w.colX[len(w.columns)]
+}
+
+// layoutColumns caches the offset of every column from the first scrolling
+// one, so columnX doesn't sum the widths again for every column. It runs at
+// the start of Update and Draw, and again when a column is resized or moved.
+func (w *Table[R]) layoutColumns() {
+	w.colX = w.colX[:0]
+	x := 0
+	for i, c := range w.columns {
+		w.colX = append(w.colX, x)
+		if i >= w.frozen() {
+			x += w.columnWidth(c)
+		}
+	}
+	w.colX = append(w.colX, x)
+}
+
+func (w *Table[R]) maxScrollX() int {
+	r := w.Measure(false)
+	return max(w.contentWidth()-(r.Dx()-w.frozenWidth()), 0)
+}
+
+// columnX returns the logical (left-to-right) x of display column i.
+func (w *Table[R]) columnX(i int) int {
+	r := w.Measure(false)
+	if i < w.frozen() {
+		return r.Min.X
+	}
+
+	if len(w.colX) != len(w.columns)+1 {
+		w.layoutColumns()
+	}
+	return r.Min.X + w.frozenWidth() - w.scrollX + w.colX[i]
+}
+
+// mirror maps the logical span [x, x+width) to the screen x of its start;
+// with width 1 it also maps a screen pixel back to a logical one.
+func (w *Table[R]) mirror(x, width int) int {
+	if !w.rtl {
+		return x
+	}
+	r := w.Measure(false)
+	return r.Min.X + r.Max.X - x - width
+}
+
+// columnAt returns the display column at logical x and whether x is on its
+// trailing edge (the resize handle).
+func (w *Table[R]) columnAt(x int) (int, bool) {
+	r := w.Measure(false)
+	if x < r.Min.X || x >= r.Max.X {
+		return -1, false
+	}
+
+	grip := w.Theme().SpaceS
+	if fw := w.frozenWidth(); fw > 0 && x < r.Min.X+fw {
+		return 0, x >= r.Min.X+fw-grip
+	}
+
+	for i := w.frozen(); i < len(w.columns); i++ {
+		cx, cw := w.columnX(i), w.columnWidth(w.columns[i])
+		if x >= cx && x < cx+cw {
+			return i, x >= cx+cw-grip
+		}
+	}
+	return -1, false
+}
+
+// posAt returns the view position of the row under p, or -1.
+func (w *Table[R]) posAt(p image.Point) int {
+	_, body := w.areas()
+	if !p.In(body) {
+		return -1
+	}
+
+	top := body.Min.Y
+	if w.height > 0 {
+		top -= w.scroll.ScrollY
+	}
+	i := (p.Y - top) / w.rowH()
+	if i < 0 || i >= len(w.order) {
+		return -1
+	}
+	return i
+}
+
+func (w *Table[R]) changed() {
+	w.reveal = true
+	w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange, Value: w.Cursor()})
+}
+
+func (w *Table[R]) onPointerDown(e uikit.Event) bool {
+	if !w.IsEnabled() || e.Pointer == nil {
+		return false
+	}
+
+	if p := w.posAt(e.Pointer.Position); p >= 0 {
+		w.sel.pick(p, shortcutPressed(), shiftPressed())
+		w.changed()
+	}
+	return false
+}
+
+func (w *Table[R]) Update(ctx *uikit.Context) {
+	r := w.Measure(false)
+	w.SetFrame(r.Min.X, r.Min.Y, r.Dx())
+	w.rtl = w.IsRTL(ctx)
+	w.layoutColumns()
+	theme := ctx.Theme()
+	header, body := w.areas()
+
+	if w.IsEnabled() {
+		w.updateHeader(ctx, header)
+	}
+
+	if w.IsEnabled() && w.IsFocused() {
+		page := 10
+		if w.height > 0 {
+			page = max(body.Dy()/w.rowH()-1, 1)
+		}
+
+		changed, activate := w.sel.handleKeys(len(w.order), page)
+		if changed {
+			w.changed()
+		}
+		if activate && w.OnActivate != nil {
+			w.OnActivate(w.Cursor())
+		}
+
+		back, fwd := ebiten.KeyLeft, ebiten.KeyRight
+		if w.rtl {
+			back, fwd = fwd, back
+		}
+		switch {
+		case keyRepeat(back):
+			w.scrollX -= theme.ControlH * 2
+		case keyRepeat(fwd):
+			w.scrollX += theme.ControlH * 2
+		}
+	}
+
+	// Sideways scrolling: horizontal wheel, or Shift with the vertical one.
+	inside := ctx.Pointer().Position.In(r)
+	wx, wy := ebiten.Wheel()
+	if shiftPressed() {
+		wx, wy = wx+wy, 0
+	}
+	if inside && wx != 0 {
+		d := int(wx * float64(theme.ControlH))
+		if w.rtl {
+			d = -d
+		}
+		w.scrollX -= d
+	}
+	w.scrollX = clampInt(w.scrollX, 0, w.maxScrollX())
+
+	if w.height <= 0 {
+		w.reveal = false
+		return
+	}
+
+	contentH := len(w.order) * w.rowH()
+	if !shiftPressed() {
+		w.scroll.Update(ctx, body, contentH)
+	}
+	if w.reveal && w.sel.cursor >= 0 {
+		top := w.sel.cursor * w.rowH()
+		w.scroll.Reveal(top, top+w.rowH(), body.Dy())
+	}
+	w.reveal = false
+	w.scroll.Clamp(body.Dy(), contentH)
+}
+
+// updateHeader handles header presses: sort on click, move on drag, resize
+// from a column edge.
+func (w *Table[R]) updateHeader(ctx *uikit.Context, header image.Rectangle) {
+	ptr := ctx.Pointer()
+	x := w.mirror(ptr.Position.X, 1)
+	theme := ctx.Theme()
+
+	if ptr.IsJustDown && ptr.Position.In(header) {
+		i, edge := w.columnAt(x)
+		if i < 0 {
+			return
+		}
+
+		c := w.columns[i]
+		w.drag, w.dragCol, w.dragX, w.dragWidth = tableDragPress, c, x, w.columnWidth(c)
+		if edge {
+			w.drag = tableDragResize
+		}
+		ctx.CapturePointer(w)
+		return
+	}
+
+	if w.drag == tableDragNone {
+		return
+	}
+	if ctx.PointerCapture() != w || !(ptr.IsDown || ptr.IsJustUp) {
+		w.drag, w.dragCol = tableDragNone, nil
+		return
+	}
+
+	c := w.dragCol
+	switch w.drag {
+	case tableDragResize:
+		c.Width = max(w.dragWidth+x-w.dragX, c.MinWidth, theme.ControlH)
+		w.layoutColumns()
+	case tableDragPress:
+		d := x - w.dragX
+		if (d > theme.SpaceS || d < -theme.SpaceS) && w.indexOf(c) >= w.frozen() {
+			w.drag = tableDragMove
+		}
+	case tableDragMove:
+		// The column moves past a neighbour once the pointer crosses the
+		// neighbour's midpoint. Measured from the new position, the pointer
+		// is then on the far side of the neighbour's midpoint again, so a
+		// narrow column dragged over a wide one doesn't swap back.
+		i := w.indexOf(c)
+		for i+1 < len(w.columns) && x >= w.columnX(i+1)+w.columnWidth(w.columns[i+1])/2 {
+			w.MoveColumn(i, i+1)
+			i++
+		}
+		for i-1 >= w.frozen() && x < w.columnX(i-1)+w.columnWidth(w.columns[i-1])/2 {
+			w.MoveColumn(i, i-1)
+			i--
+		}
+	}
+
+	if ptr.IsJustUp {
+		if w.drag == tableDragPress && c.Less != nil {
+			w.SortBy(c, w.sortCol == c && !w.sortDesc)
+		}
+		w.drag, w.dragCol = tableDragNone, nil
+	}
+}
+
+func (w *Table[R]) indexOf(c *TableColumn[R]) int {
+	for i, col := range w.columns {
+		if col == c {
+			return i
+		}
+	}
+	return -1
+}
+
+func (w *Table[R]) Draw(ctx *uikit.Context, dst *ebiten.Image) {
+	theme := ctx.Theme()
+	r := w.Measure(false)
+	w.rtl = w.IsRTL(ctx)
+	w.layoutColumns()
+	header, body := w.areas()
+
+	clip := r.Intersect(dst.Bounds())
+	if clip.Empty() {
+		return
+	}
+
+	bg := theme.SurfaceColor
+	if !w.IsEnabled() {
+		bg = theme.SurfacePressedColor
+	}
+	if w.height > 0 {
+		w.DrawRoundedRect(dst, r, theme.Radius, bg)
+	} else {
+		w.DrawRoundedRect(dst, clip, 0, bg)
+	}
+
+	rowH := w.rowH()
+	top := body.Min.Y
+	if w.height > 0 {
+		top -= w.scroll.ScrollY
+	}
+
+	bodyClip := body.Intersect(clip)
+	first, last := 0, 0
+	if !bodyClip.Empty() {
+		first = max((bodyClip.Min.Y-top)/rowH, 0)
+		last = min((bodyClip.Max.Y-top+rowH-1)/rowH, len(w.order))
+	}
+
+	hover := -1
+	if w.IsHovered() && w.IsEnabled() && w.drag == tableDragNone {
+		hover = w.posAt(ctx.Pointer().Position)
+	}
+
+	// Row backgrounds span the full width.
+	bodySub := dst.SubImage(bodyClip).(*ebiten.Image)
+	for p := first; p < last; p++ {
+		row := image.Rect(r.Min.X, top+p*rowH, r.Max.X, top+(p+1)*rowH)
+		switch {
+		case w.sel.selected[p]:
+			w.DrawRoundedRect(bodySub, row, 0, theme.SelectionColor)
+		case p == hover:
+			w.DrawRoundedRect(bodySub, row, 0, theme.SurfaceHoverColor)
+		}
+	}
+
+	// The scrolling columns are clipped out of the frozen column area.
+	fw := min(w.frozenWidth(), r.Dx())
+	sx := w.mirror(r.Min.X+fw, r.Dx()-fw)
+	scrollArea := image.Rect(sx, r.Min.Y, sx+r.Dx()-fw, r.Max.Y)
+	fx := w.mirror(r.Min.X, fw)
+	frozenArea := image.Rect(fx, r.Min.Y, fx+fw, r.Max.Y)
+
+	for i := range w.columns {
+		area := scrollArea
+		if i < w.frozen() {
+			area = frozenArea
+		}
+		w.drawColumn(ctx, dst, i, area.Intersect(clip), header, top, first, last)
+	}
+
+	if fw > 0 {
+		x := w.mirror(r.Min.X+fw-theme.BorderW, theme.BorderW)
+		w.DrawRoundedRect(dst, image.Rect(x, r.Min.Y, x+theme.BorderW, r.Max.Y).Intersect(clip), 0, theme.BorderColor)
+	}
+
+	// Cursor ring over the cells.
+	if c := w.sel.cursor; c >= first && c < last && w.IsFocused() && w.IsEnabled() {
+		row := image.Rect(r.Min.X, top+c*rowH, r.Max.X, top+(c+1)*rowH)
+		w.DrawRoundedBorder(bodySub, row, theme.Radius, theme.FocusRingW, theme.FocusColor)
+	}
+
+	line := image.Rect(r.Min.X, header.Max.Y-theme.BorderW, r.Max.X, header.Max.Y)
+	w.DrawRoundedRect(dst, line.Intersect(clip), 0, theme.BorderColor)
+
+	if w.height > 0 {
+		w.DrawRoundedBorder(dst, r, theme.Radius, theme.BorderW, theme.BorderColor)
+		if w.IsFocused() && w.IsEnabled() && w.sel.cursor < 0 {
+			w.DrawRoundedBorder(dst, r, theme.Radius, theme.FocusRingW, theme.FocusColor)
+		}
+		w.scroll.DrawBar(bodySub, theme, bodyClip.Dx(), bodyClip.Dy(), len(w.order)*rowH)
+	}
+	w.drawHBar(dst, bodyClip)
+}
+
+// drawColumn draws the header cell and visible cells of display column i,
+// clipped to area.
+func (w *Table[R]) drawColumn(ctx *uikit.Context, dst *ebiten.Image, i int, area, header image.Rectangle, top, first, last int) {
+	if area.Empty() {
+		return
+	}
+
+	theme := ctx.Theme()
+	c := w.columns[i]
+	cw := w.columnWidth(c)
+	x := w.mirror(w.columnX(i), cw)
+	col := image.Rect(x, area.Min.Y, x+cw, area.Max.Y)
+	if !col.Overlaps(area) {
+		return
+	}
+	sub := dst.SubImage(col.Intersect(area)).(*ebiten.Image)
+
+	textCol := theme.TextColor
+	if !w.IsEnabled() {
+		textCol = theme.DisabledColor
+	}
+
+	// Header cell.
+	hc := image.Rect(col.Min.X, header.Min.Y, col.Max.X, header.Max.Y)
+	hbg := theme.SurfacePressedColor
+	if c == w.dragCol && w.drag == tableDragMove {
+		hbg = theme.SurfaceHoverColor
+	}
+	w.DrawRoundedRect(sub, hc, 0, hbg)
+
+	sep := hc.Max.X - theme.BorderW
+	if w.rtl {
+		sep = hc.Min.X
+	}
+	w.DrawRoundedRect(sub, image.Rect(sep, hc.Min.Y, sep+theme.BorderW, hc.Max.Y), 0, theme.BorderColor)
+
+	label := hc.Inset(theme.PadX / 2)
+	if c == w.sortCol {
+		size := float32(theme.ControlH) / 3
+		cx := float32(label.Max.X) - size
+		if w.rtl {
+			cx = float32(label.Min.X) + size
+		}
+		dy := float32(-1)
+		if w.sortDesc {
+			dy = 1
+		}
+		drawChevron(sub, cx, float32(hc.Min.Y+hc.Dy()/2), size, 0, dy, textCol)
+		if w.rtl {
+			label.Min.X += int(size * 2)
+		} else {
+			label.Max.X -= int(size * 2)
+		}
+	}
+	w.drawCell(theme, sub, c.Title, label, false, textCol)
+
+	// Body cells.
+	for p := first; p < last; p++ {
+		y := top + p*w.rowH()
+		cell := image.Rect(col.Min.X, y, col.Max.X, y+w.rowH()).Inset(theme.PadX / 2)
+		w.drawCell(theme, sub, c.Text(w.rows[w.order[p]]), cell, c.AlignEnd, textCol)
+	}
+}
+
+func (w *Table[R]) drawCell(theme *uikit.Theme, dst *ebiten.Image, s string, r image.Rectangle, end bool, col color.RGBA) {
+	t := theme.Text()
+	t.SetColor(col)
+
+	cy := r.Min.Y + r.Dy()/2
+	if end != w.rtl {
+		t.SetAlign(etxt.Right | etxt.VertCenter)
+		t.Draw(dst, visualText(s, w.rtl), r.Max.X, cy)
+	} else {
+		t.SetAlign(etxt.Left | etxt.VertCenter)
+		t.Draw(dst, visualText(s, w.rtl), r.Min.X, cy)
+	}
+}
+
+// drawHBar draws the horizontal scrollbar at the bottom of the rows when
+// the columns overflow.
+func (w *Table[R]) drawHBar(dst *ebiten.Image, body image.Rectangle) {
+	maxX := w.maxScrollX()
+	if maxX == 0 || body.Empty() {
+		return
+	}
+
+	theme := w.Theme()
+	fw := w.frozenWidth()
+	track := w.Measure(false).Dx() - fw
+	if track <= 0 {
+		return
+	}
+
+	thick := max(theme.SpaceS/2, theme.BorderW)
+	thumb := max(theme.ControlH/2, track*track/(track+maxX))
+	tx := (track - thumb) * w.scrollX / maxX
+
+	x := w.mirror(body.Min.X+fw, track)
+	y := body.Max.Y - thick
+	thumbX := x + tx
+	if w.rtl {
+		thumbX = x + track - tx - thumb
+	}
+
+	vector.DrawFilledRect(dst, float32(x), float32(y), float32(track), float32(thick), theme.BorderColor, false)
+	vector.DrawFilledRect(dst, float32(thumbX), float32(y), float32(thumb), float32(thick), theme.FocusColor, false)
+}
@@ -0,0 +1,616 @@
+package widget
+
+import (
+	"image"
+	"math"
+
+	"github.com/erparts/go-uikit"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/tinne26/etxt"
+)
+
+var _ uikit.Widget = (*Tabs)(nil)
+var _ uikit.Hittable = (*Tabs)(nil)
+
+// Tabs shows one page out of many, selected from a strip of tabs.
+// - Each tab owns a uikit.Layout page. Only the active page is updated,
+// drawn and exposed through Children, so hidden pages take no part in focus
+// traversal or hit testing.
+// - Pages added with AddLazyTab are built on first activation.
+// - Closable tabs draw a close button; OnClose may veto the close.
+// - When the tabs do not fit, the strip scrolls (arrows, wheel) and keeps
+// the active tab in view.
+// - The strip is a single Tab stop: arrows move between tabs, Delete closes
+// the active one. Ctrl+Tab / Ctrl+Shift+Tab (or Ctrl+PageDown / PageUp)
+// switch tabs while the focus is anywhere inside the widget.
+// - EventValueChange carries the active index in Event.Value.
+type Tabs struct {
+	uikit.Base
+
+	tabs   []*tabItem
+	active int // -1 when there are no tabs
+
+	// OnClose is called before a tab is closed by the user; returning false
+	// keeps the tab open.
+	OnClose func(index int) bool
+
+	scroll   int // strip scroll offset, in pixels from the leading edge
+	contentW int // total width of the tabs
+	dirty    bool
+	reveal   bool // scroll the active tab into view on next Update
+	rtl      bool // direction of the last Update, for pointer handlers
+
+	height int
+}
+
+type tabItem struct {
+	title    string
+	closable bool
+	build    func() uikit.Layout
+	page     uikit.Layout
+
+	x, w int // position within the strip content, from the leading edge
+}
+
+func NewTabs(theme *uikit.Theme) *Tabs {
+	cfg := uikit.NewWidgetBaseConfig(theme)
+	cfg.DrawSurface = false
+	cfg.DrawBorder = false
+	cfg.DrawFocus = false // drawn around the active tab instead
+
+	w := &Tabs{
+		Base:   uikit.NewBase(cfg),
+		active: -1,
+	}
+	w.Base.HeightCalculator = w.heightCalculator
+	w.Base.On(uikit.EventPointerDown, w.onPointerDown, false)
+	w.Base.On(uikit.EventClick, w.onClick, false)
+
+	return w
+}
+
+func (w *Tabs) heightCalculator() int {
+	if w.height > 0 {
+		return w.height
+	}
+
+	h := w.Theme().ControlH
+	if p := w.Page(w.active); p != nil {
+		h += p.Measure(true).Dy()
+	}
+	return h
+}
+
+func (w *Tabs) Focusable() bool { return len(w.tabs) > 0 }
+
+// SetHeight fixes the widget height; the active page gets the remaining
+// space below the strip. Use 0 to follow the page height.
+func (w *Tabs) SetHeight(h int) {
+	w.height = h
+}
+
+// AddTab appends a tab showing page and returns its index. The first tab
+// added becomes active.
+func (w *Tabs) AddTab(title string, page uikit.Layout) int {
+	return w.addTab(&tabItem{title: title, page: page})
+}
+
+// AddLazyTab appends a tab whose page is built the first time it is shown.
+func (w *Tabs) AddLazyTab(title string, build func() uikit.Layout) int {
+	return w.addTab(&tabItem{title: title, build: build})
+}
+
+func (w *Tabs) addTab(t *tabItem) int {
+	w.tabs = append(w.tabs, t)
+	w.dirty = true
+	if w.active < 0 {
+		w.SetActive(0)
+	}
+	return len(w.tabs) - 1
+}
+
+// RemoveTab removes tab i without consulting OnClose. When the active tab is
+// removed, its neighbour becomes active.
+func (w *Tabs) RemoveTab(i int) {
+	if i < 0 || i >= len(w.tabs) {
+		return
+	}
+
+	removed := w.tabs[i]
+	w.tabs = append(w.tabs[:i], w.tabs[i+1:]...)
+	w.dirty = true
+
+	switch {
+	case i < w.active:
+		w.active--
+	case i == w.active:
+		if removed.page != nil {
+			resetWidgetState(removed.page)
+		}
+
+		w.active = -1
+		if len(w.tabs) > 0 {
+			w.SetActive(min(i, len(w.tabs)-1))
+		} else {
+			w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange, Value: -1})
+		}
+	}
+}
+
+// CloseTab closes tab i as if its close button was clicked. It reports
+// whether the tab was closed.
+func (w *Tabs) CloseTab(i int) bool {
+	if i < 0 || i >= len(w.tabs) {
+		return false
+	}
+	if w.OnClose != nil && !w.OnClose(i) {
+		return false
+	}
+
+	w.RemoveTab(i)
+	return true
+}
+
+func (w *Tabs) TabCount() int { return len(w.tabs) }
+
+func (w *Tabs) Title(i int) string {
+	if i < 0 || i >= len(w.tabs) {
+		return ""
+	}
+	return w.tabs[i].title
+}
+
+func (w *Tabs) SetTitle(i int, s string) {
+	if i < 0 || i >= len(w.tabs) {
+		return
+	}
+	w.tabs[i].title = s
+	w.dirty = true
+}
+
+// SetClosable shows or hides the close button of tab i.
+func (w *Tabs) SetClosable(i int, v bool) {
+	if i < 0 || i >= len(w.tabs) {
+		return
+	}
+	w.tabs[i].closable = v
+	w.dirty = true
+}
+
+func (w *Tabs) IsClosable(i int) bool {
+	return i >= 0 && i < len(w.tabs) && w.tabs[i].closable
+}
+
+// Page returns the page of tab i, or nil if it has not been built yet.
+func (w *Tabs) Page(i int) uikit.Layout {
+	if i < 0 || i >= len(w.tabs) {
+		return nil
+	}
+	return w.tabs[i].page
+}
+
+// Active returns the active tab, or -1.
+func (w *Tabs) Active() int { return w.active }
+
+// SetActive shows tab i, building its page if needed.
+func (w *Tabs) SetActive(i int) {
+	if i < 0 || i >= len(w.tabs) || i == w.active {
+		return
+	}
+
+	if old := w.Page(w.active); old != nil {
+		resetWidgetState(old)
+	}
+
+	t := w.tabs[i]
+	if t.page == nil && t.build != nil {
+		t.page = t.build()
+	}
+
+	w.active = i
+	w.reveal = true
+	w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange, Value: i})
+}
+
+// Children exposes the active page only, which keeps the hidden pages out
+// of the Context focus list.
+func (w *Tabs) Children() []uikit.Widget {
+	if p := w.Page(w.active); p != nil {
+		return []uikit.Widget{p}
+	}
+	return nil
+}
+
+// resetWidgetState clears the interaction state of a subtree leaving the
+// widget tree, since the Context no longer refreshes it.
+func resetWidgetState(w uikit.Widget) {
+	w.SetHovered(false)
+	w.SetPressed(false)
+	w.SetFocused(false)
+
+	if l, ok := any(w).(interface{ Children() []uikit.Widget }); ok {
+		for _, ch := range l.Children() {
+			resetWidgetState(ch)
+		}
+	}
+}
+
+// containsWidget reports whether target is w or one of its descendants.
+func containsWidget(w, target uikit.Widget) bool {
+	if w == target {
+		return true
+	}
+
+	if l, ok := any(w).(interface{ Children() []uikit.Widget }); ok {
+		for _, ch := range l.Children() {
+			if containsWidget(ch, target) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// measureTabs computes the tab widths from their titles.
+func (w *Tabs) measureTabs(theme *uikit.Theme) {
+	t := theme.Text()
+	x := 0
+	for _, tab := range w.tabs {
+		tw := t.Measure(tab.title).IntWidth() + theme.PadX*2
+		if tab.closable {
+			tw += w.closeSize(theme) + theme.SpaceS
+		}
+
+		tab.x, tab.w = x, max(tw, theme.ControlH)
+		x += tab.w
+	}
+
+	w.contentW = x
+	w.dirty = false
+}
+
+func (w *Tabs) closeSize(theme *uikit.Theme) int {
+	return theme.ControlH / 2
+}
+
+func (w *Tabs) strip() image.Rectangle {
+	r := w.Measure(false)
+	return image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+w.Theme().ControlH)
+}
+
+func (w *Tabs) overflows() bool {
+	return w.contentW > w.strip().Dx()
+}
+
+// stripAreas splits the strip into the visible tab area and, when the tabs
+// overflow, the scroll arrows (prev on the leading side).
+func (w *Tabs) stripAreas(theme *uikit.Theme, rtl bool) (view, prev, next image.Rectangle) {
+	s := w.strip()
+	if !w.overflows() {
+		return s, image.Rectangle{}, image.Rectangle{}
+	}
+
+	aw := theme.ControlH * 2 / 3
+	left := image.Rect(s.Min.X, s.Min.Y, s.Min.X+aw, s.Max.Y)
+	right := image.Rect(s.Max.X-aw, s.Min.Y, s.Max.X, s.Max.Y)
+	view = image.Rect(left.Max.X, s.Min.Y, right.Min.X, s.Max.Y)
+	if rtl {
+		return view, right, left
+	}
+	return view, left, right
+}
+
+// tabRect returns the screen rect of tab i, mirrored in RTL.
+func (w *Tabs) tabRect(i int, view image.Rectangle, rtl bool) image.Rectangle {
+	t := w.tabs[i]
+	x := view.Min.X + t.x - w.scroll
+	if rtl {
+		x = view.Max.X - (t.x - w.scroll) - t.w
+	}
+	return image.Rect(x, view.Min.Y, x+t.w, view.Max.Y)
+}
+
+// closeRect returns the close button of a tab, on its trailing side.
+func (w *Tabs) closeRect(theme *uikit.Theme, tab image.Rectangle, rtl bool) image.Rectangle {
+	size := w.closeSize(theme)
+	x := tab.Max.X - theme.PadX - size
+	if rtl {
+		x = tab.Min.X + theme.PadX
+	}
+	y := tab.Min.Y + (tab.Dy()-size)/2
+	return image.Rect(x, y, x+size, y+size)
+}
+
+// tabAt returns the tab under p and whether p is on its close button.
+func (w *Tabs) tabAt(theme *uikit.Theme, p image.Point, rtl bool) (int, bool) {
+	view, _, _ := w.stripAreas(theme, rtl)
+	if !p.In(view) {
+		return -1, false
+	}
+
+	for i, t := range w.tabs {
+		r := w.tabRect(i, view, rtl)
+		if p.In(r) {
+			return i, t.closable && p.In(w.closeRect(theme, r, rtl))
+		}
+	}
+	return -1, false
+}
+
+func (w *Tabs) maxScroll(theme *uikit.Theme) int {
+	view, _, _ := w.stripAreas(theme, false)
+	return max(w.contentW-view.Dx(), 0)
+}
+
+func (w *Tabs) scrollBy(theme *uikit.Theme, dx int) {
+	w.scroll = clampInt(w.scroll+dx, 0, w.maxScroll(theme))
+}
+
+// revealActive scrolls the strip so the active tab is fully visible.
+func (w *Tabs) revealActive(theme *uikit.Theme) {
+	if w.active < 0 {
+		return
+	}
+
+	view, _, _ := w.stripAreas(theme, false)
+	t := w.tabs[w.active]
+	if t.x < w.scroll {
+		w.scroll = t.x
+	} else if t.x+t.w > w.scroll+view.Dx() {
+		w.scroll = t.x + t.w - view.Dx()
+	}
+	w.scroll = clampInt(w.scroll, 0, w.maxScroll(theme))
+}
+
+// HitTest limits pointer hits to the strip; the page receives the rest.
+func (w *Tabs) HitTest(ctx *uikit.Context, pos image.Point) bool {
+	return pos.In(w.strip())
+}
+
+func (w *Tabs) onPointerDown(e uikit.Event) bool {
+	if !w.IsEnabled() || e.Pointer == nil {
+		return false
+	}
+
+	rtl := w.rtl
+	if i, onClose := w.tabAt(w.Theme(), e.Pointer.Position, rtl); i >= 0 && !onClose {
+		w.SetActive(i)
+	}
+	return false
+}
+
+func (w *Tabs) onClick(e uikit.Event) bool {
+	if !w.IsEnabled() || e.Pointer == nil {
+		return false
+	}
+
+	theme := w.Theme()
+	rtl := w.rtl
+	p := e.Pointer.Position
+
+	_, prev, next := w.stripAreas(theme, rtl)
+	switch {
+	case p.In(prev):
+		w.scrollBy(theme, -theme.ControlH*2)
+	case p.In(next):
+		w.scrollBy(theme, theme.ControlH*2)
+	default:
+		if i, onClose := w.tabAt(theme, p, rtl); onClose {
+			w.CloseTab(i)
+		}
+	}
+	return false
+}
+
+func (w *Tabs) Update(ctx *uikit.Context) {
+	theme := ctx.Theme()
+	rtl := w.IsRTL(ctx)
+	w.rtl = rtl
+
+	r := w.Measure(false)
+	w.SetFrame(r.Min.X, r.Min.Y, r.Dx())
+
+	if w.dirty {
+		w.measureTabs(theme)
+		w.reveal = true
+	}
+
+	if w.IsEnabled() {
+		w.updateKeys(ctx, rtl)
+	}
+
+	// The strip scrolls with the wheel, vertical or horizontal.
+	if w.IsHovered() && w.overflows() {
+		wx, wy := ebiten.Wheel()
+		if d := wx - wy; d != 0 {
+			if rtl {
+				d = -d
+			}
+			w.scrollBy(theme, int(math.Round(d*float64(theme.ControlH))))
+		}
+	}
+
+	if w.reveal {
+		w.revealActive(theme)
+		w.reveal = false
+	}
+	w.scroll = clampInt(w.scroll, 0, w.maxScroll(theme))
+
+	page := w.Page(w.active)
+	if page == nil {
+		return
+	}
+
+	if w.height > 0 {
+		page.SetHeight(max(w.height-theme.ControlH, 0))
+	}
+	page.SetFrame(r.Min.X, r.Min.Y+theme.ControlH, r.Dx())
+	page.Update(ctx)
+}
+
+func (w *Tabs) updateKeys(ctx *uikit.Context, rtl bool) {
+	n := len(w.tabs)
+	if n == 0 {
+		return
+	}
+
+	// Ctrl+Tab works from anywhere inside the widget; the focus moves to the
+	// strip so it does not stay on a widget of the hidden page.
+	focused := ctx.Focused()
+	within := focused != nil && containsWidget(w, focused)
+	if within && ebiten.IsKeyPressed(ebiten.KeyControl) {
+		step := 0
+		switch {
+		case inpututil.IsKeyJustPressed(ebiten.KeyTab) && shiftPressed():
+			step = -1
+		case inpututil.IsKeyJustPressed(ebiten.KeyTab):
+			step = 1
+		case keyRepeat(ebiten.KeyPageUp):
+			step = -1
+		case keyRepeat(ebiten.KeyPageDown):
+			step = 1
+		}
+
+		if step != 0 {
+			ctx.SetFocus(w)
+			w.SetActive((w.active + step + n) % n)
+			return
+		}
+	}
+
+	if !w.IsFocused() {
+		return
+	}
+
+	prev, next := ebiten.KeyLeft, ebiten.KeyRight
+	if rtl {
+		prev, next = next, prev
+	}
+
+	switch {
+	case keyRepeat(next):
+		w.SetActive((w.active + 1) % n)
+	case keyRepeat(prev):
+		w.SetActive((w.active - 1 + n) % n)
+	case inpututil.IsKeyJustPressed(ebiten.KeyHome):
+		w.SetActive(0)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnd):
+		w.SetActive(n - 1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyDelete) && w.IsClosable(w.active):
+		w.CloseTab(w.active)
+	}
+}
+
+func (w *Tabs) Draw(ctx *uikit.Context, dst *ebiten.Image) {
+	w.Base.Draw(ctx, dst)
+
+	theme := ctx.Theme()
+	rtl := w.IsRTL(ctx)
+	strip := w.strip()
+	view, prev, next := w.stripAreas(theme, rtl)
+
+	// Baseline under the strip; the active tab is drawn over it.
+	line := image.Rect(strip.Min.X, strip.Max.Y-theme.BorderW, strip.Max.X, strip.Max.Y)
+	w.DrawRoundedRect(dst, line, 0, theme.BorderColor)
+
+	if w.dirty {
+		w.measureTabs(theme)
+	}
+
+	ptr := ctx.Pointer()
+	hoverTab, hoverClose := -1, false
+	if w.IsHovered() && w.IsEnabled() {
+		hoverTab, hoverClose = w.tabAt(theme, ptr.Position, rtl)
+	}
+
+	clip := dst.SubImage(view).(*ebiten.Image)
+	t := theme.Text()
+	for i, tab := range w.tabs {
+		r := w.tabRect(i, view, rtl)
+		if !r.Overlaps(view) {
+			continue
+		}
+
+		textCol := theme.MutedTextColor
+		switch {
+		case !w.IsEnabled():
+			textCol = theme.DisabledColor
+		case i == w.active:
+			textCol = theme.TextColor
+		}
+
+		if i == w.active {
+			w.DrawRoundedRect(clip, r, theme.Radius, theme.SurfaceColor)
+			w.DrawRoundedBorder(clip, r, theme.Radius, theme.BorderW, theme.BorderColor)
+			// Open the bottom edge so the tab joins its page.
+			w.DrawRoundedRect(clip, image.Rect(r.Min.X+theme.BorderW, r.Max.Y-theme.Radius, r.Max.X-theme.BorderW, r.Max.Y), 0, theme.SurfaceColor)
+		} else if i == hoverTab {
+			w.DrawRoundedRect(clip, r, theme.Radius, theme.SurfaceHoverColor)
+		}
+
+		label := r
+		if tab.closable {
+			cr := w.closeRect(theme, r, rtl)
+			if i == hoverTab && hoverClose {
+				w.DrawRoundedRect(clip, cr, cr.Dx()/2, theme.SurfacePressedColor)
+			}
+
+			t.SetColor(textCol)
+			t.SetAlign(etxt.Center)
+			t.Draw(clip, "×", cr.Min.X+cr.Dx()/2, cr.Min.Y+cr.Dy()/2)
+
+			if rtl {
+				label.Min.X = cr.Max.X
+			} else {
+				label.Max.X = cr.Min.X
+			}
+		}
+
+		t.SetColor(textCol)
+		t.SetAlign(etxt.Center)
+		t.Draw(clip, visualText(tab.title, rtl), label.Min.X+label.Dx()/2, label.Min.Y+label.Dy()/2)
+
+		if i == w.active && w.IsFocused() && w.IsEnabled() {
+			w.DrawRoundedBorder(clip, r.Inset(theme.BorderW), theme.Radius, theme.FocusRingW, theme.FocusColor)
+		}
+	}
+
+	if !prev.Empty() {
+		w.drawArrow(theme, dst, prev, "‹", "›", w.scroll > 0, rtl)
+		w.drawArrow(theme, dst, next, "›", "‹", w.scroll < w.maxScroll(theme), rtl)
+	}
+
+	if page := w.Page(w.active); page != nil {
+		page.Draw(ctx, dst)
+	}
+}
+
+// drawArrow draws a strip scroll button; the glyph is mirrored in RTL.
+func (w *Tabs) drawArrow(theme *uikit.Theme, dst *ebiten.Image, r image.Rectangle, glyph, rtlGlyph string, enabled, rtl bool) {
+	w.DrawRoundedRect(dst, r, 0, theme.BackgroundColor)
+
+	col := theme.TextColor
+	if !enabled || !w.IsEnabled() {
+		col = theme.DisabledColor
+	}
+	if rtl {
+		glyph = rtlGlyph
+	}
+
+	t := theme.Text()
+	t.SetColor(col)
+	t.SetAlign(etxt.Center)
+	t.Draw(dst, glyph, r.Min.X+r.Dx()/2, r.Min.Y+r.Dy()/2)
+}
+
+// DrawOverlay forwards to the active page, so its dropdowns escape clipping.
+func (w *Tabs) DrawOverlay(ctx *uikit.Context, dst *ebiten.Image) {
+	if !w.IsVisible() {
+		return
+	}
+
+	if page := w.Page(w.active); page != nil {
+		page.DrawOverlay(ctx, dst)
+	}
+}
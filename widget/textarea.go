@@ -1,11 +1,11 @@
 package widget
 
 import (
+	"image"
 	"math"
 	"strings"
 
 	"github.com/erparts/go-uikit"
-	"github.com/erparts/go-uikit/common"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
@@ -13,18 +13,55 @@ import (
 )
 
 var _ uikit.Widget = (*TextArea)(nil)
+var _ uikit.Hittable = (*TextArea)(nil)
+var _ uikit.OverlayWidget = (*TextArea)(nil)
 
 // TextArea is a multi-line text editor with internal vertical scrolling.
+// Caret movement, deletion and max length work on grapheme clusters.
+// Each line is laid out with the Unicode bidirectional algorithm; in RTL
+// widgets lines are right-aligned and the arrow keys are mirrored.
+// An optional Highlighter styles lines with coloured, bold/italic, underlined
+// or highlighted spans (syntax highlighting, inline validation errors).
+//
+// The text is kept in a rope with a line index, so editing large documents
+// costs O(log n) and only the visible lines are measured and drawn.
+//
+// Ctrl+Z / Ctrl+Shift+Z (or Ctrl+Y) undo and redo. Ctrl+F opens an incremental
+// search panel (Ctrl+H with replace): Enter and Shift+Enter jump between the
+// matches, F3 works from the text as well.
+//
+// With ShowGutter, line numbers and markers (see SetMarker) are drawn on the
+// leading side; clicking a number selects its line.
 type TextArea struct {
 	uikit.Base
 
-	text        string
+	buf         *textBuffer
 	placeholder string
 
 	lines  int
 	Scroll uikit.Scroller
 
-	// Caret config (end-of-text caret)
+	caret    int // byte offset, always on a grapheme boundary
+	anchor   int // selection anchor; equal to caret when nothing is selected
+	goalX    int // remembered x for vertical caret movement, -1 if unset
+	dragging bool
+	maxLen   int
+	clusters int // grapheme clusters of the text, -1 until a max length needs them
+
+	highlighter    Highlighter
+	docHighlighter DocumentHighlighter
+	spanCache      map[int]cachedSpans
+	docLines       []docLine // highlighted prefix of the text
+
+	history textHistory
+	find    *findBar
+
+	// Gutter config
+	ShowGutter           bool
+	HighlightCurrentLine bool
+	markers              map[int]GutterMarker
+
+	// Caret config
 	CaretWidthPx  int
 	CaretBlinkMs  int
 	CaretMarginPx int
@@ -40,16 +77,23 @@ func NewTextArea(theme *uikit.Theme, placeholder string) *TextArea {
 
 	w := &TextArea{
 		Base:          uikit.NewBase(cfg),
+		buf:           newTextBuffer(""),
 		placeholder:   placeholder,
 		lines:         5,
+		goalX:         -1,
 		CaretWidthPx:  2,
 		CaretBlinkMs:  600,
 		CaretMarginPx: 0,
+		spanCache:     map[int]cachedSpans{},
+		clusters:      -1,
 	}
 
 	w.Scroll = uikit.NewScroller()
 	w.Scroll.Scrollbar = uikit.ScrollbarAlways
 
+	w.find = newFindBar(theme)
+	w.find.query.On(uikit.EventValueChange, w.onFindQueryChange, false)
+
 	w.Base.HeightCalculator = w.calculateHeight
 	return w
 }
@@ -70,13 +114,37 @@ func (w *TextArea) calculateHeight() int {
 
 func (w *TextArea) Focusable() bool { return true }
 func (w *TextArea) WantsIME() bool  { return true }
-func (w *TextArea) Text() string    { return w.text }
+
+func (w *TextArea) handlesEnter() bool { return true }
+
+// Text returns the whole text. It is materialised on demand and cached until
+// the next edit; prefer LineCount/Line for large documents.
+func (w *TextArea) Text() string { return w.buf.String() }
+
+// Len returns the text size in bytes.
+func (w *TextArea) Len() int { return w.buf.Len() }
+
+// LineCount returns the number of lines of the text.
+func (w *TextArea) LineCount() int { return w.buf.LineCount() }
+
+// Line returns the text of line i, without its line break.
+func (w *TextArea) Line(i int) string { return w.buf.Line(i) }
 
 func (w *TextArea) SetText(s string) {
-	if w.text == s {
+	if w.buf.Equal(s) {
 		return
 	}
-	w.text = s
+	if w.maxLen > 0 {
+		s = truncateGraphemes(s, w.maxLen)
+	}
+	w.buf = newTextBuffer(s)
+	w.clusters = -1
+	w.caret = len(s)
+	w.anchor = w.caret
+	w.goalX = -1
+	w.history.clear()
+	w.find.invalidate()
+	w.InvalidateHighlight()
 	w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange})
 }
 
@@ -87,10 +155,385 @@ func (w *TextArea) SetLines(n int) {
 	w.lines = n
 }
 
-// setTextInternal updates the text without multiple dispatches.
-// Caller decides whether to Dispatch once.
-func (w *TextArea) setTextInternal(s string) {
-	w.text = s
+// SetMaxLength limits the text to n user-perceived characters (grapheme
+// clusters). Use 0 for unlimited.
+func (w *TextArea) SetMaxLength(n int) {
+	w.maxLen = max(n, 0)
+	if w.maxLen > 0 && w.clusterCount() > w.maxLen {
+		w.SetText(truncateGraphemes(w.Text(), w.maxLen))
+	}
+}
+
+func (w *TextArea) MaxLength() int { return w.maxLen }
+
+// Caret returns the caret position as a byte offset into Text().
+func (w *TextArea) Caret() int { return w.caret }
+
+// SetCaret moves the caret to the grapheme boundary at or before byte offset i
+// and clears the selection.
+func (w *TextArea) SetCaret(i int) {
+	w.SetSelection(i, i)
+}
+
+// Selection returns the selected byte range of Text(); start == end when
+// nothing is selected.
+func (w *TextArea) Selection() (start, end int) {
+	return min(w.caret, w.anchor), max(w.caret, w.anchor)
+}
+
+// SetSelection selects the text between anchor and caret (byte offsets,
+// snapped to grapheme boundaries). The caret ends at the caret offset.
+func (w *TextArea) SetSelection(anchor, caret int) {
+	w.anchor = w.snap(anchor)
+	w.caret = w.snap(caret)
+	w.goalX = -1
+	w.caretTick = 0
+}
+
+// SelectAll selects the whole text.
+func (w *TextArea) SelectAll() {
+	w.SetSelection(0, w.buf.Len())
+}
+
+// SelectedText returns the currently selected text.
+func (w *TextArea) SelectedText() string {
+	a, b := w.Selection()
+	return w.buf.Slice(a, b)
+}
+
+// SetHighlighter sets a per-line highlighter. Use nil to disable highlighting.
+func (w *TextArea) SetHighlighter(h Highlighter) {
+	w.highlighter = h
+	w.docHighlighter = nil
+	w.InvalidateHighlight()
+}
+
+// SetDocumentHighlighter sets a highlighter carrying state from line to line.
+// It replaces any per-line highlighter.
+func (w *TextArea) SetDocumentHighlighter(h DocumentHighlighter) {
+	w.docHighlighter = h
+	w.highlighter = nil
+	w.InvalidateHighlight()
+}
+
+// InvalidateHighlight drops all cached spans. Call it when the highlight
+// depends on external state that changed (e.g. a new list of errors).
+func (w *TextArea) InvalidateHighlight() {
+	clear(w.spanCache)
+	w.docLines = w.docLines[:0]
+}
+
+// invalidateFrom drops the cached spans of the given line and the following
+// ones, whose indexes may have shifted.
+func (w *TextArea) invalidateFrom(line int) {
+	for k := range w.spanCache {
+		if k >= line {
+			delete(w.spanCache, k)
+		}
+	}
+	w.docLines = w.docLines[:min(len(w.docLines), line)]
+}
+
+// lineSpans returns the (cached) spans of a line.
+func (w *TextArea) lineSpans(index int, line string) []Span {
+	if w.docHighlighter != nil {
+		// Lines are highlighted in order, from the first one not cached up
+		// to the requested one.
+		for i := len(w.docLines); i <= index; i++ {
+			var state any
+			if i > 0 {
+				state = w.docLines[i-1].state
+			}
+			spans, next := w.docHighlighter.HighlightNext(w.buf.Line(i), state)
+			w.docLines = append(w.docLines, docLine{spans: spans, state: next})
+		}
+		return w.docLines[index].spans
+	}
+
+	if w.highlighter == nil {
+		return nil
+	}
+
+	if c, ok := w.spanCache[index]; ok && c.text == line {
+		return c.spans
+	}
+
+	spans := w.highlighter.HighlightLine(index, line)
+	w.spanCache[index] = cachedSpans{text: line, spans: spans}
+	return spans
+}
+
+// insertAt inserts s at offset i, trimmed to the max length, and returns the
+// inserted text. Caller decides whether to Dispatch.
+func (w *TextArea) insertAt(i int, s string) string {
+	if w.maxLen > 0 {
+		before := w.buf.Slice(w.buf.LineStart(w.buf.LineOf(i)), i)
+		s = fitGraphemes(w.clusterCount(), before[prevGrapheme(before, len(before)):], s, w.maxLen)
+	}
+	if s == "" {
+		return ""
+	}
+
+	w.history.record(textEdit{at: i, inserted: s})
+	w.shiftMarkers(w.buf.LineOf(i), w.wholeLines(i, ""), 0, strings.Count(s, "\n"))
+	w.invalidateFrom(w.buf.LineOf(i))
+	w.replaceText(i, 0, s)
+	w.find.invalidate()
+	return s
+}
+
+// deleteRange removes the bytes in [a, b). Caller decides whether to Dispatch.
+func (w *TextArea) deleteRange(a, b int) {
+	if a >= b {
+		return
+	}
+
+	removed := w.buf.Slice(a, b)
+	w.history.record(textEdit{at: a, removed: removed})
+	w.shiftMarkers(w.buf.LineOf(a), w.wholeLines(a, removed), strings.Count(removed, "\n"), 0)
+	w.invalidateFrom(w.buf.LineOf(a))
+	w.replaceText(a, b-a, "")
+	w.find.invalidate()
+}
+
+// applyEdit replaces n bytes at offset at with s, without recording it.
+func (w *TextArea) applyEdit(at, n int, s string) {
+	removed := w.buf.Slice(at, at+n)
+	w.shiftMarkers(w.buf.LineOf(at), w.wholeLines(at, removed), strings.Count(removed, "\n"), strings.Count(s, "\n"))
+	w.invalidateFrom(w.buf.LineOf(at))
+	w.replaceText(at, n, s)
+	w.find.invalidate()
+}
+
+// replaceText replaces n bytes at offset at with s in the buffer. A counted
+// cluster total is updated from the lines around the edit only.
+func (w *TextArea) replaceText(at, n int, s string) {
+	if w.clusters < 0 {
+		w.buf.Delete(at, at+n)
+		w.buf.Insert(at, s)
+		return
+	}
+
+	old := w.linesClusters(at, at+n)
+	w.buf.Delete(at, at+n)
+	w.buf.Insert(at, s)
+	w.clusters += w.linesClusters(at, at+len(s)) - old
+}
+
+// linesClusters counts the clusters of the lines touching [a, b], including
+// the last line break. A line break always ends a cluster, so the counts of
+// separate runs of lines add up to the total.
+func (w *TextArea) linesClusters(a, b int) int {
+	from := w.buf.LineStart(w.buf.LineOf(a))
+	to := min(w.buf.LineEnd(w.buf.LineOf(b))+1, w.buf.Len())
+	return graphemeCount(w.buf.Slice(from, to))
+}
+
+// clusterCount returns the number of grapheme clusters of the text. It is
+// counted once, then kept up to date by every edit.
+func (w *TextArea) clusterCount() int {
+	if w.clusters < 0 {
+		w.clusters = graphemeCount(w.buf.String())
+	}
+	return w.clusters
+}
+
+// editGroup runs fn as a single undo step and dispatches one value change.
+func (w *TextArea) editGroup(fn func()) {
+	size := len(w.history.undo)
+	started := w.history.current == nil
+	if started {
+		w.history.begin(w.caret, w.anchor)
+	}
+
+	fn()
+
+	if started {
+		w.history.commit(w.caret, w.anchor)
+		w.history.seal()
+	}
+	if !started || len(w.history.undo) != size {
+		w.goalX = -1
+		w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange})
+	}
+}
+
+// CanUndo reports whether there is an edit to undo.
+func (w *TextArea) CanUndo() bool { return len(w.history.undo) > 0 }
+
+// CanRedo reports whether there is an undone edit to redo.
+func (w *TextArea) CanRedo() bool { return len(w.history.redo) > 0 }
+
+// Undo reverts the last edit step and restores the caret it had before.
+func (w *TextArea) Undo() bool {
+	h := &w.history
+	n := len(h.undo)
+	if n == 0 {
+		return false
+	}
+
+	g := h.undo[n-1]
+	h.undo = h.undo[:n-1]
+	for i := len(g.edits) - 1; i >= 0; i-- {
+		e := g.edits[i]
+		w.applyEdit(e.at, len(e.inserted), e.removed)
+	}
+	h.redo = append(h.redo, g)
+	h.seal()
+
+	w.restoreCaret(g.caretBefore, g.anchorBefore)
+	return true
+}
+
+// Redo applies again the last undone edit step.
+func (w *TextArea) Redo() bool {
+	h := &w.history
+	n := len(h.redo)
+	if n == 0 {
+		return false
+	}
+
+	g := h.redo[n-1]
+	h.redo = h.redo[:n-1]
+	for _, e := range g.edits {
+		w.applyEdit(e.at, len(e.removed), e.inserted)
+	}
+	h.undo = append(h.undo, g)
+	h.seal()
+
+	w.restoreCaret(g.caretAfter, g.anchorAfter)
+	return true
+}
+
+func (w *TextArea) restoreCaret(caret, anchor int) {
+	w.SetSelection(anchor, caret)
+	w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange})
+	w.revealCaret()
+}
+
+// The grapheme helpers below only look at the line containing the offset, so
+// they stay cheap on large documents. Line breaks count as one cluster
+// ("\r\n" included).
+
+func (w *TextArea) snap(i int) int {
+	i = clampInt(i, 0, w.buf.Len())
+	li := w.buf.LineOf(i)
+	ls := w.buf.LineStart(li)
+	line := w.buf.Line(li)
+	if i-ls > len(line) {
+		return ls + len(line)
+	}
+	if i-ls == len(line) && strings.HasSuffix(line, "\r") && i < w.buf.Len() {
+		return i - 1
+	}
+	return ls + snapGrapheme(line, i-ls)
+}
+
+func (w *TextArea) prevOffset(i int) int {
+	li := w.buf.LineOf(i)
+	ls := w.buf.LineStart(li)
+	if i > ls {
+		return ls + prevGrapheme(w.buf.Line(li), i-ls)
+	}
+	if i == 0 {
+		return 0
+	}
+
+	// Step over the line break of the previous line.
+	if i >= 2 && w.buf.Slice(i-2, i-1) == "\r" {
+		return i - 2
+	}
+	return i - 1
+}
+
+func (w *TextArea) nextOffset(i int) int {
+	li := w.buf.LineOf(i)
+	ls, le := w.buf.LineStart(li), w.buf.LineEnd(li)
+	if i >= le {
+		return min(i+1, w.buf.Len())
+	}
+
+	line := w.buf.Line(li)
+	next := ls + nextGrapheme(line, i-ls)
+	if next == le && strings.HasSuffix(line, "\r") && le < w.buf.Len() {
+		return le + 1
+	}
+	return next
+}
+
+func measureFunc(t *etxt.Renderer) func(string) int {
+	return func(s string) int { return t.Measure(s).IntWidth() }
+}
+
+// textLine is a line laid out for display inside the content area.
+type textLine struct {
+	*bidiLine
+	originX int // lines are right-aligned in RTL
+	measure func(string) int
+	styleAt func(start int) TextStyle
+}
+
+// layoutLine lays out one line with its highlight spans.
+func layoutLine(t *etxt.Renderer, line string, spans []Span, def TextStyle, contentW int, rtl bool) textLine {
+	l := textLine{bidiLine: layoutBidi(line, rtl), measure: measureFunc(t)}
+	l.styleAt = func(start int) TextStyle { return styleAt(spans, start, def) }
+	if hasFonts(spans) {
+		l.measure = l.styledMeasure(t, l.styleAt)
+	}
+
+	if rtl {
+		l.originX = contentW - l.measure(l.visual)
+	}
+	return l
+}
+
+// layoutIndex lays out line index of the text and returns it with its start
+// offset.
+func (w *TextArea) layoutIndex(t *etxt.Renderer, index, contentW int, rtl bool) (textLine, int) {
+	line := w.buf.Line(index)
+	return layoutLine(t, line, w.lineSpans(index, line), TextStyle{}, contentW, rtl), w.buf.LineStart(index)
+}
+
+// caretAtPoint returns the text offset under a point relative to the content
+// origin (already including the scroll offset).
+func (w *TextArea) caretAtPoint(t *etxt.Renderer, x, y, lineH, contentW int, rtl bool) int {
+	index := clampInt(y/lineH, 0, w.buf.LineCount()-1)
+	if y < 0 {
+		index = 0
+	}
+
+	l, start := w.layoutIndex(t, index, contentW, rtl)
+	return start + l.offsetAt(x-l.originX, l.measure)
+}
+
+// caretX returns the x of the caret inside the content area.
+func (w *TextArea) caretX(t *etxt.Renderer, caret, contentW int, rtl bool) int {
+	l, start := w.layoutIndex(t, w.buf.LineOf(caret), contentW, rtl)
+	return l.originX + l.caretX(caret-start, l.measure)
+}
+
+// moveVertical moves the caret by one line up (dir < 0) or down, keeping the
+// horizontal position remembered in goalX.
+func (w *TextArea) moveVertical(t *etxt.Renderer, caret, goalX, dir, contentW int, rtl bool) (int, int) {
+	if goalX < 0 {
+		goalX = w.caretX(t, caret, contentW, rtl)
+	}
+
+	target := w.buf.LineOf(caret) + dir
+	if target < 0 {
+		return 0, goalX
+	}
+	if target >= w.buf.LineCount() {
+		return w.buf.Len(), goalX
+	}
+
+	l, start := w.layoutIndex(t, target, contentW, rtl)
+	return start + l.offsetAt(goalX-l.originX, l.measure), goalX
+}
+
+// contentHeight returns the scrollable height of the text.
+func (w *TextArea) contentHeight(lineH, viewH int) int {
+	return max(w.buf.LineCount()*lineH, viewH)
 }
 
 func (w *TextArea) Update(ctx *uikit.Context) {
@@ -110,7 +553,8 @@ func (w *TextArea) Update(ctx *uikit.Context) {
 	}
 
 	theme := ctx.Theme()
-	content := common.Inset(r, theme.PadX, theme.PadY)
+	rtl := w.IsRTL(ctx)
+	content, gutter := w.areas(theme, r, rtl)
 
 	// Only line-height is needed for scroll math.
 	t := theme.Text()
@@ -119,109 +563,245 @@ func (w *TextArea) Update(ctx *uikit.Context) {
 		lineH = 1
 	}
 
-	// Compute line count without splitting
-	lineCount := 1
-	if w.text != "" {
-		lineCount = 1 + strings.Count(w.text, "\n")
+	w.Scroll.Update(ctx, content, w.contentHeight(lineH, content.Dy()))
+
+	if !enabled {
+		return
 	}
 
-	contentH := lineCount * lineH
-	if contentH < content.Dy() {
-		contentH = content.Dy()
+	// Pointer places the caret at the nearest cluster boundary; dragging or
+	// Shift extends the selection.
+	ptr := ctx.Pointer()
+	hit := func() int {
+		p := ptr.Position.Sub(content.Min)
+		return w.caretAtPoint(t, p.X, p.Y+w.Scroll.ScrollY, lineH, content.Dx(), rtl)
+	}
+	overFind := w.find.open && ptr.Position.In(w.find.layout(theme, r, rtl).panel)
+	if ptr.IsJustDown && ptr.Position.In(content) && !overFind {
+		w.find.keyboard = false
+		w.history.seal()
+		if shiftPressed() {
+			w.SetSelection(w.anchor, hit())
+		} else {
+			w.SetCaret(hit())
+		}
+		w.dragging = true
+	} else if ptr.IsJustDown && ptr.Position.In(gutter) && !overFind {
+		w.find.keyboard = false
+		w.history.seal()
+		line := clampInt((ptr.Position.Y-gutter.Min.Y+w.Scroll.ScrollY)/lineH, 0, w.buf.LineCount()-1)
+		w.selectLines(line, shiftPressed())
+		w.revealCaret()
+	} else if w.dragging && ptr.IsDown {
+		w.SetSelection(w.anchor, hit())
+	}
+	if !ptr.IsDown {
+		w.dragging = false
 	}
 
-	w.Scroll.Update(ctx, content, contentH)
+	if w.find.open && w.updateFind(ctx, rtl) {
+		return
+	}
 
-	if !focused || !enabled {
+	if !focused {
 		return
 	}
 
-	original := w.text
-	text := original
+	// Shortcuts acting on the whole widget.
+	switch {
+	case shortcutPressed() && inpututil.IsKeyJustPressed(ebiten.KeyF):
+		w.OpenFind(false)
+		return
+	case shortcutPressed() && inpututil.IsKeyJustPressed(ebiten.KeyH):
+		w.OpenFind(true)
+		return
+	case shortcutPressed() && (inpututil.IsKeyJustPressed(ebiten.KeyY) || shiftPressed() && keyRepeat(ebiten.KeyZ)):
+		w.Redo()
+		return
+	case shortcutPressed() && keyRepeat(ebiten.KeyZ):
+		w.Undo()
+		return
+	case w.find.open && inpututil.IsKeyJustPressed(ebiten.KeyF3):
+		if shiftPressed() {
+			w.FindPrevious()
+		} else {
+			w.FindNext()
+		}
+		return
+	case w.find.open && inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		w.CloseFind()
+		return
+	}
+
+	changed := false
+	caret, anchor := w.caret, w.anchor
+	goalX := w.goalX
 
 	// --- IME / chars (buffer reuse) ---
 	w.inputBuf = ebiten.AppendInputChars(w.inputBuf[:0])
 	w.appendBuf = w.appendBuf[:0]
 
+	deleteSelection := func() bool {
+		if caret == anchor {
+			return false
+		}
+		a, b := min(caret, anchor), max(caret, anchor)
+		w.deleteRange(a, b)
+		caret, anchor = a, a
+		goalX = -1
+		changed = true
+		return true
+	}
+
+	insert := func(s string) {
+		deleteSelection()
+		s = w.insertAt(caret, s)
+		caret += len(s)
+		anchor = caret
+		goalX = -1
+		changed = changed || s != ""
+	}
+
+	w.history.begin(caret, anchor)
+
 	flushAppend := func() {
 		if len(w.appendBuf) == 0 {
 			return
 		}
-		text += string(w.appendBuf)
+		insert(string(w.appendBuf))
 		w.appendBuf = w.appendBuf[:0]
 	}
 
-	changed := false
+	backspace := func() {
+		if deleteSelection() {
+			return
+		}
+		prev := w.prevOffset(caret)
+		w.deleteRange(prev, caret)
+		changed = changed || prev != caret
+		caret, anchor = prev, prev
+		goalX = -1
+	}
+
+	move := func(to int) {
+		caret = to
+		goalX = -1
+		if !shiftPressed() {
+			anchor = caret
+		}
+	}
 
 	for _, ch := range w.inputBuf {
 		// backspace can come as '\b' or DEL
 		if ch == '\b' || ch == 0x7f {
 			flushAppend()
-			text = removeLastRune(text)
-			changed = true
+			backspace()
 			continue
 		}
 
 		// newline
 		if ch == '\n' || ch == '\r' {
 			flushAppend()
-			text += "\n"
-			changed = true
+			insert("\n")
 			continue
 		}
 
-		// control chars ignored
-		if ch < 0x20 {
+		// control chars and runes typed along with shortcuts are ignored
+		if ch < 0x20 || shortcutPressed() {
 			continue
 		}
 
 		w.appendBuf = append(w.appendBuf, ch)
 	}
 
-	if len(w.appendBuf) > 0 {
-		flushAppend()
-		changed = true
-	}
+	flushAppend()
 
 	// Fallback key handling for platforms that don't deliver via AppendInputChars
-	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) {
-		text = removeLastRune(text)
-		changed = true
+	if keyRepeat(ebiten.KeyBackspace) {
+		backspace()
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyKPEnter) {
-		text += "\n"
-		changed = true
+	if keyRepeat(ebiten.KeyDelete) && !deleteSelection() {
+		next := w.nextOffset(caret)
+		w.deleteRange(caret, next)
+		changed = changed || next != caret
+		goalX = -1
+	}
+	if keyRepeat(ebiten.KeyEnter) || keyRepeat(ebiten.KeyKPEnter) {
+		insert("\n")
+	}
+
+	// Caret movement (logical order; arrows are mirrored in RTL).
+	back, forward := ebiten.KeyLeft, ebiten.KeyRight
+	if rtl {
+		back, forward = forward, back
+	}
+
+	switch {
+	case shortcutPressed() && inpututil.IsKeyJustPressed(ebiten.KeyA):
+		caret, anchor = w.buf.Len(), 0
+	case keyRepeat(back):
+		move(w.prevOffset(caret))
+	case keyRepeat(forward):
+		move(w.nextOffset(caret))
+	case keyRepeat(ebiten.KeyUp):
+		caret, goalX = w.moveVertical(t, caret, goalX, -1, content.Dx(), rtl)
+		if !shiftPressed() {
+			anchor = caret
+		}
+	case keyRepeat(ebiten.KeyDown):
+		caret, goalX = w.moveVertical(t, caret, goalX, 1, content.Dx(), rtl)
+		if !shiftPressed() {
+			anchor = caret
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyHome):
+		move(w.buf.LineStart(w.buf.LineOf(caret)))
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnd):
+		move(w.buf.LineEnd(w.buf.LineOf(caret)))
 	}
 
 	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
 		ctx.SetFocus(nil)
 	}
 
-	// Apply once + Dispatch once
-	if changed && text != original {
-		w.setTextInternal(text)
+	moved := caret != w.caret || anchor != w.anchor
+	w.caret, w.anchor = caret, anchor
+	w.goalX = goalX
+
+	if moved && !changed {
+		w.history.seal()
+	}
+	w.history.commit(caret, anchor)
+
+	// Dispatch once
+	if changed {
 		w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange})
+	}
 
-		// Scroll-to-caret (caret at end of text)
-		newLineCount := 1 + strings.Count(w.text, "\n")
-		caretBottom := newLineCount * lineH
+	if moved || changed {
+		w.caretTick = 0
+		w.scrollToCaret(lineH, content.Dy())
+	}
+}
 
-		minScroll := caretBottom - content.Dy()
-		if minScroll < 0 {
-			minScroll = 0
-		}
-		if w.Scroll.ScrollY < minScroll {
-			w.Scroll.ScrollY = minScroll
-		}
+// revealCaret scrolls the caret line into view outside of Update.
+func (w *TextArea) revealCaret() {
+	theme := w.Theme()
+	lineH := max(theme.Text().Measure(" ").IntHeight(), 1)
+	content, _ := w.areas(theme, w.Measure(false), false) // only the height is used
+	w.scrollToCaret(lineH, content.Dy())
+}
 
-		maxScroll := (newLineCount*lineH - content.Dy())
-		if maxScroll < 0 {
-			maxScroll = 0
-		}
-		if w.Scroll.ScrollY > maxScroll {
-			w.Scroll.ScrollY = maxScroll
-		}
+// scrollToCaret scrolls the minimum needed to show the caret line.
+func (w *TextArea) scrollToCaret(lineH, viewH int) {
+	caretTop := w.buf.LineOf(w.caret) * lineH
+	if caretTop < w.Scroll.ScrollY {
+		w.Scroll.ScrollY = caretTop
+	}
+	if caretTop+lineH > w.Scroll.ScrollY+viewH {
+		w.Scroll.ScrollY = caretTop + lineH - viewH
 	}
+
+	w.Scroll.Clamp(viewH, w.buf.LineCount()*lineH)
 }
 
 func (w *TextArea) Draw(ctx *uikit.Context, dst *ebiten.Image) {
@@ -231,7 +811,8 @@ func (w *TextArea) Draw(ctx *uikit.Context, dst *ebiten.Image) {
 	}
 
 	theme := ctx.Theme()
-	content := common.Inset(r, theme.PadX, theme.PadY)
+	rtl := w.IsRTL(ctx)
+	content, gutter := w.areas(theme, r, rtl)
 
 	// Base visuals
 	w.DrawSurface(ctx, dst, r)
@@ -252,61 +833,73 @@ func (w *TextArea) Draw(ctx *uikit.Context, dst *ebiten.Image) {
 		lineH = 1
 	}
 
-	startY := -w.Scroll.ScrollY
+	selStart, selEnd := w.Selection()
+	showSel := w.IsFocused() && selStart != selEnd
 
-	// Placeholder
-	drawStr := w.text
-	col := theme.TextColor
-	if drawStr == "" && !w.IsFocused() {
-		drawStr = w.placeholder
-		col = theme.MutedTextColor
-	}
+	// Only the lines intersecting the viewport are laid out and drawn.
+	first := max(w.Scroll.ScrollY/lineH, 0)
+	last := min((w.Scroll.ScrollY+content.Dy())/lineH, w.buf.LineCount()-1)
 
-	t.SetColor(col)
+	if w.buf.Len() == 0 && !w.IsFocused() {
+		// Placeholder
+		def := TextStyle{Color: theme.MutedTextColor}
+		for i, line := range strings.Split(w.placeholder, "\n") {
+			if i < first || i > last {
+				continue
+			}
+			l := layoutLine(t, line, nil, def, content.Dx(), rtl)
+			l.drawPieces(t, sub, ox+l.originX, oy+i*lineH-w.Scroll.ScrollY, l.styleAt)
+		}
+	} else {
+		def := TextStyle{Color: theme.TextColor}
+		caretLine := w.buf.LineOf(w.caret)
+		matches := w.visibleMatches(w.buf.LineStart(first), w.buf.LineStart(last)+len(w.buf.Line(last)))
+		for index := first; index <= last; index++ {
+			line := w.buf.Line(index)
+			from := w.buf.LineStart(index)
+			end := from + len(line)
+			y := index*lineH - w.Scroll.ScrollY
+
+			if w.HighlightCurrentLine && index == caretLine {
+				w.DrawRoundedRect(sub, image.Rect(ox, oy+y, ox+content.Dx(), oy+y+lineH), 0, theme.SurfaceHoverColor)
+			}
 
-	// Draw lines WITHOUT strings.Split allocation
-	// Iterate and draw between '\n' boundaries.
-	y := startY
-	from := 0
-	for i := 0; i <= len(drawStr); i++ {
-		end := i
-		if i == len(drawStr) || drawStr[i] == '\n' {
-			line := drawStr[from:end]
-			t.Draw(sub, line, ox, oy+y)
-			y += lineH
-			from = i + 1
+			l := layoutLine(t, line, w.lineSpans(index, line), def, content.Dx(), rtl)
+
+			for _, m := range matchesIn(matches, from, end) {
+				for _, sp := range l.selectionSpans(m[0]-from, m[1]-from, l.measure) {
+					hl := image.Rect(ox+l.originX+sp[0], oy+y, ox+l.originX+sp[1], oy+y+lineH)
+					w.DrawRoundedRect(sub, hl, 0, theme.MatchColor)
+				}
+			}
+
+			if showSel && selStart <= end && selEnd > from {
+				for _, sp := range l.selectionSpans(selStart-from, selEnd-from, l.measure) {
+					sel := image.Rect(ox+l.originX+sp[0], oy+y, ox+l.originX+sp[1], oy+y+lineH)
+					w.DrawRoundedRect(sub, sel, 0, theme.SelectionColor)
+				}
+			}
+
+			l.drawPieces(t, sub, ox+l.originX, oy+y, l.styleAt)
 		}
 	}
 
-	// Scrollbar
-	lineCount := 1
-	if w.text != "" {
-		lineCount = 1 + strings.Count(w.text, "\n")
-	}
-	contentH := lineCount * lineH
-	if contentH < content.Dy() {
-		contentH = content.Dy()
+	if w.ShowGutter {
+		w.drawGutter(ctx, dst, gutter, first, last, lineH, rtl)
 	}
 
+	// Scrollbar
+	contentH := w.contentHeight(lineH, content.Dy())
+
 	w.Scroll.DrawBar(sub, theme, content.Dx(), content.Dy(), contentH)
 
-	// Caret at end
-	if w.IsFocused() && w.IsEnabled() && w.CaretWidthPx > 0 {
+	// Caret
+	if w.IsFocused() && w.IsEnabled() && w.CaretWidthPx > 0 && !w.find.keyboard {
 		blinkFrames := int(math.Max(1, float64(w.CaretBlinkMs)/1000.0*60.0))
 		if (w.caretTick/blinkFrames)%2 == 0 {
+			lineIdx := w.buf.LineOf(w.caret)
 
-			// last line text = substring after last '\n'
-			lastIdx := strings.LastIndex(w.text, "\n")
-			lastLine := w.text
-			lineIdx := 0
-			if lastIdx >= 0 {
-				lastLine = w.text[lastIdx+1:]
-				lineIdx = 1 + strings.Count(w.text[:lastIdx], "\n")
-			}
-
-			wBefore := t.Measure(lastLine).ImageRect().Dx()
-
-			cx := wBefore + w.CaretMarginPx
+			cx := w.caretX(t, w.caret, content.Dx(), rtl) + w.CaretMarginPx
 			cy := (lineIdx * lineH) - w.Scroll.ScrollY
 
 			if cx < 0 {
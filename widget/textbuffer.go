@@ -0,0 +1,312 @@
+package widget
+
+import (
+	"math/rand/v2"
+	"strings"
+)
+
+// textBuffer stores a document as a rope: a treap of text chunks where every
+// node keeps the byte length and newline count of its subtree. Edits and
+// offset/line lookups are O(log n); the full string is only built on demand.
+type textBuffer struct {
+	root *ropeNode
+
+	str   string // materialised text, valid while strOK
+	strOK bool
+}
+
+// ropeChunk is the maximum chunk size created when inserting text.
+const ropeChunk = 512
+
+type ropeNode struct {
+	left, right *ropeNode
+	prio        uint32
+
+	chunk      string
+	chunkLines int
+
+	size  int // bytes in subtree
+	lines int // '\n' in subtree
+}
+
+func newRopeLeaf(s string) *ropeNode {
+	n := &ropeNode{prio: rand.Uint32(), chunk: s, chunkLines: strings.Count(s, "\n")}
+	n.update()
+	return n
+}
+
+func (n *ropeNode) update() {
+	n.size = len(n.chunk) + ropeSize(n.left) + ropeSize(n.right)
+	n.lines = n.chunkLines + ropeLines(n.left) + ropeLines(n.right)
+}
+
+func ropeSize(n *ropeNode) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func ropeLines(n *ropeNode) int {
+	if n == nil {
+		return 0
+	}
+	return n.lines
+}
+
+func ropeMerge(a, b *ropeNode) *ropeNode {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	if a.prio > b.prio {
+		a.right = ropeMerge(a.right, b)
+		a.update()
+		return a
+	}
+
+	b.left = ropeMerge(a, b.left)
+	b.update()
+	return b
+}
+
+// ropeSplit splits n at byte offset off, cutting a chunk when needed.
+func ropeSplit(n *ropeNode, off int) (*ropeNode, *ropeNode) {
+	if n == nil {
+		return nil, nil
+	}
+
+	ls := ropeSize(n.left)
+	switch {
+	case off <= ls:
+		a, b := ropeSplit(n.left, off)
+		n.left = b
+		n.update()
+		return a, n
+	case off >= ls+len(n.chunk):
+		a, b := ropeSplit(n.right, off-ls-len(n.chunk))
+		n.right = a
+		n.update()
+		return n, b
+	}
+
+	k := off - ls
+	left, right := n.left, n.right
+	return ropeMerge(left, newRopeLeaf(n.chunk[:k])), ropeMerge(newRopeLeaf(n.chunk[k:]), right)
+}
+
+// ropeInsertInPlace appends s inside the chunk containing off when the chunk
+// stays under ropeChunk, which keeps typing from fragmenting the tree.
+func ropeInsertInPlace(n *ropeNode, off int, s string) bool {
+	if n == nil {
+		return false
+	}
+
+	ls := ropeSize(n.left)
+	var ok bool
+	switch {
+	case off < ls:
+		ok = ropeInsertInPlace(n.left, off, s)
+	case off > ls+len(n.chunk):
+		ok = ropeInsertInPlace(n.right, off-ls-len(n.chunk), s)
+	default:
+		if len(n.chunk)+len(s) > ropeChunk {
+			return false
+		}
+		k := off - ls
+		n.chunk = n.chunk[:k] + s + n.chunk[k:]
+		n.chunkLines += strings.Count(s, "\n")
+		ok = true
+	}
+
+	if ok {
+		n.update()
+	}
+	return ok
+}
+
+// ropeNthNewline returns the offset of the k-th (1-based) newline in n.
+func ropeNthNewline(n *ropeNode, k int) int {
+	ll := ropeLines(n.left)
+	if k <= ll {
+		return ropeNthNewline(n.left, k)
+	}
+
+	k -= ll
+	base := ropeSize(n.left)
+	if k <= n.chunkLines {
+		i := -1
+		for ; k > 0; k-- {
+			i += 1 + strings.IndexByte(n.chunk[i+1:], '\n')
+		}
+		return base + i
+	}
+
+	return base + len(n.chunk) + ropeNthNewline(n.right, k-n.chunkLines)
+}
+
+// ropeNewlinesBefore counts the newlines in the first off bytes of n.
+func ropeNewlinesBefore(n *ropeNode, off int) int {
+	if n == nil {
+		return 0
+	}
+
+	ls := ropeSize(n.left)
+	if off <= ls {
+		return ropeNewlinesBefore(n.left, off)
+	}
+
+	k := off - ls
+	if k <= len(n.chunk) {
+		return ropeLines(n.left) + strings.Count(n.chunk[:k], "\n")
+	}
+
+	return ropeLines(n.left) + n.chunkLines + ropeNewlinesBefore(n.right, k-len(n.chunk))
+}
+
+// ropeAppend writes the bytes of n in [a, b) to sb.
+func ropeAppend(sb *strings.Builder, n *ropeNode, a, b int) {
+	if n == nil || a >= b {
+		return
+	}
+
+	ls := ropeSize(n.left)
+	if a < ls {
+		ropeAppend(sb, n.left, a, min(b, ls))
+	}
+
+	cs, ce := max(a-ls, 0), min(b-ls, len(n.chunk))
+	if cs < ce {
+		sb.WriteString(n.chunk[cs:ce])
+	}
+
+	rs := ls + len(n.chunk)
+	if b > rs {
+		ropeAppend(sb, n.right, max(a-rs, 0), b-rs)
+	}
+}
+
+// ropeEach calls fn with the chunks of n in order, until it returns false.
+func ropeEach(n *ropeNode, fn func(chunk string) bool) bool {
+	return n == nil || ropeEach(n.left, fn) && fn(n.chunk) && ropeEach(n.right, fn)
+}
+
+func newTextBuffer(s string) *textBuffer {
+	b := &textBuffer{}
+	b.Insert(0, s)
+	return b
+}
+
+// Len returns the document size in bytes.
+func (b *textBuffer) Len() int { return ropeSize(b.root) }
+
+// LineCount returns the number of lines (at least 1).
+func (b *textBuffer) LineCount() int { return ropeLines(b.root) + 1 }
+
+// LineStart returns the offset of the first byte of line i.
+func (b *textBuffer) LineStart(i int) int {
+	i = clampInt(i, 0, b.LineCount()-1)
+	if i == 0 {
+		return 0
+	}
+	return ropeNthNewline(b.root, i) + 1
+}
+
+// LineEnd returns the offset of the newline ending line i (or the document end).
+func (b *textBuffer) LineEnd(i int) int {
+	i = clampInt(i, 0, b.LineCount()-1)
+	if i == b.LineCount()-1 {
+		return b.Len()
+	}
+	return ropeNthNewline(b.root, i+1)
+}
+
+// LineOf returns the line containing offset off.
+func (b *textBuffer) LineOf(off int) int {
+	return ropeNewlinesBefore(b.root, clampInt(off, 0, b.Len()))
+}
+
+// Line returns the text of line i without its newline.
+func (b *textBuffer) Line(i int) string {
+	return b.Slice(b.LineStart(i), b.LineEnd(i))
+}
+
+// Slice returns the text in [start, end).
+func (b *textBuffer) Slice(start, end int) string {
+	start, end = clampInt(start, 0, b.Len()), clampInt(end, 0, b.Len())
+	if b.strOK {
+		return b.str[start:end]
+	}
+
+	var sb strings.Builder
+	sb.Grow(max(end-start, 0))
+	ropeAppend(&sb, b.root, start, end)
+	return sb.String()
+}
+
+// String materialises the whole document. The result is cached until the
+// next edit.
+func (b *textBuffer) String() string {
+	if !b.strOK {
+		b.str = b.Slice(0, b.Len())
+		b.strOK = true
+	}
+	return b.str
+}
+
+// Equal reports whether the document is s, without materialising it.
+func (b *textBuffer) Equal(s string) bool {
+	if b.Len() != len(s) {
+		return false
+	}
+	if b.strOK {
+		return b.str == s
+	}
+
+	off := 0
+	return ropeEach(b.root, func(chunk string) bool {
+		ok := s[off:off+len(chunk)] == chunk
+		off += len(chunk)
+		return ok
+	})
+}
+
+// Insert inserts s at offset off.
+func (b *textBuffer) Insert(off int, s string) {
+	if s == "" {
+		return
+	}
+
+	off = clampInt(off, 0, b.Len())
+	b.strOK = false
+
+	if len(s) <= ropeChunk && ropeInsertInPlace(b.root, off, s) {
+		return
+	}
+
+	var mid *ropeNode
+	for len(s) > 0 {
+		n := min(len(s), ropeChunk)
+		mid = ropeMerge(mid, newRopeLeaf(s[:n]))
+		s = s[n:]
+	}
+
+	l, r := ropeSplit(b.root, off)
+	b.root = ropeMerge(ropeMerge(l, mid), r)
+}
+
+// Delete removes the bytes in [start, end).
+func (b *textBuffer) Delete(start, end int) {
+	start, end = clampInt(start, 0, b.Len()), clampInt(end, 0, b.Len())
+	if start >= end {
+		return
+	}
+
+	b.strOK = false
+	l, rest := ropeSplit(b.root, start)
+	_, r := ropeSplit(rest, end-start)
+	b.root = ropeMerge(l, r)
+}
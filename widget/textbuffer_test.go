@@ -0,0 +1,136 @@
+package widget
+
+import (
+	"strings"
+	"testing"
+)
+
+// checkBuffer compares every query of b against the plain string model.
+func checkBuffer(t *testing.T, b *textBuffer, model string) {
+	t.Helper()
+
+	if b.Len() != len(model) {
+		t.Fatalf("Len() = %d, want %d", b.Len(), len(model))
+	}
+	if got := b.Slice(0, b.Len()); got != model {
+		t.Fatalf("Slice(0, Len) = %q, want %q", got, model)
+	}
+	if !b.Equal(model) {
+		t.Fatalf("Equal(%q) = false", model)
+	}
+	if b.Equal(model+"x") || (model != "" && b.Equal("\x00"+model[1:])) {
+		t.Fatalf("Equal reports a different text as equal")
+	}
+
+	lines := strings.Split(model, "\n")
+	if b.LineCount() != len(lines) {
+		t.Fatalf("LineCount() = %d, want %d", b.LineCount(), len(lines))
+	}
+
+	start := 0
+	for i, line := range lines {
+		if got := b.LineStart(i); got != start {
+			t.Errorf("LineStart(%d) = %d, want %d", i, got, start)
+		}
+		if got := b.Line(i); got != line {
+			t.Errorf("Line(%d) = %q, want %q", i, got, line)
+		}
+		start += len(line) + 1
+	}
+
+	for off := 0; off <= len(model); off++ {
+		if got, want := b.LineOf(off), strings.Count(model[:off], "\n"); got != want {
+			t.Fatalf("LineOf(%d) = %d, want %d", off, got, want)
+		}
+	}
+
+	for _, r := range [][2]int{{0, 0}, {0, 1}, {len(model) / 3, len(model) / 2}, {len(model) - 1, len(model)}, {ropeChunk - 1, ropeChunk + 1}} {
+		a, z := clampInt(r[0], 0, len(model)), clampInt(r[1], 0, len(model))
+		if got := b.Slice(a, z); got != model[a:max(a, z)] {
+			t.Errorf("Slice(%d, %d) = %q, want %q", a, z, got, model[a:max(a, z)])
+		}
+	}
+}
+
+func TestTextBuffer(t *testing.T) {
+	long := strings.Repeat("0123456789abcde\n", 100) // 1600 bytes, several chunks
+
+	type op struct {
+		insert   bool
+		at, end  int // delete [at, end)
+		s        string
+		atEnd    bool // at is the document end
+		fromBack int  // delete the last fromBack bytes
+	}
+	tests := []struct {
+		name    string
+		initial string
+		ops     []op
+	}{
+		{"empty", "", nil},
+		{"single line", "hello", nil},
+		{"trailing newline", "a\nb\n", nil},
+		{"only newlines", "\n\n\n", nil},
+		{"insert at start", "b\nc", []op{{insert: true, at: 0, s: "a\n"}}},
+		{"insert at end", "a\nb", []op{{insert: true, atEnd: true, s: "\nc"}}},
+		{"insert in the middle of a line", "ac\nd", []op{{insert: true, at: 1, s: "b"}}},
+		{"insert newline splits a line", "abcd", []op{{insert: true, at: 2, s: "\n"}}},
+		{"insert into empty", "", []op{{insert: true, at: 0, s: "x\ny"}}},
+		{"delete at start", "a\nb\nc", []op{{at: 0, end: 2}}},
+		{"delete at end", "a\nb\nc", []op{{fromBack: 2}}},
+		{"delete joins lines", "ab\ncd", []op{{at: 2, end: 3}}},
+		{"delete everything", "a\nb", []op{{at: 0, end: 3}}},
+		{"multi-chunk document", long, nil},
+		{"insert at chunk boundary", long, []op{{insert: true, at: ropeChunk, s: "X\nY"}}},
+		{"insert before chunk boundary", long, []op{{insert: true, at: ropeChunk - 1, s: "\n"}}},
+		{"insert larger than a chunk", "a\nb", []op{{insert: true, at: 1, s: long}}},
+		{"delete across chunks", long, []op{{at: ropeChunk - 3, end: 2*ropeChunk + 3}}},
+		{"delete a chunk exactly", long, []op{{at: ropeChunk, end: 2 * ropeChunk}}},
+		{"typing grows a chunk", long, []op{
+			{insert: true, at: 10, s: "a"},
+			{insert: true, at: 11, s: "b"},
+			{insert: true, at: 12, s: "\n"},
+			{at: 11, end: 13},
+		}},
+		{"mixed edits", long, []op{
+			{insert: true, at: 0, s: "start\n"},
+			{insert: true, atEnd: true, s: "end"},
+			{at: 100, end: 700},
+			{insert: true, at: ropeChunk, s: strings.Repeat("z", ropeChunk+7)},
+			{fromBack: 5},
+			{at: 0, end: 1},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTextBuffer(tt.initial)
+			model := tt.initial
+			checkBuffer(t, b, model)
+
+			for _, o := range tt.ops {
+				switch {
+				case o.insert:
+					at := o.at
+					if o.atEnd {
+						at = len(model)
+					}
+					b.Insert(at, o.s)
+					model = model[:at] + o.s + model[at:]
+				case o.fromBack > 0:
+					b.Delete(len(model)-o.fromBack, len(model))
+					model = model[:len(model)-o.fromBack]
+				default:
+					b.Delete(o.at, o.end)
+					model = model[:o.at] + model[o.end:]
+				}
+				checkBuffer(t, b, model)
+
+				// The cached string must be dropped by the next edit.
+				if b.String() != model {
+					t.Fatalf("String() = %q, want %q", b.String(), model)
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package widget
 
 import (
+	"image"
 	"math"
 	"time"
 	"unicode/utf8"
@@ -16,6 +17,9 @@ var _ uikit.Widget = (*TextInput)(nil)
 
 // TextInput is a single-line input box (no label).
 // Height and proportions come from Theme; external layout controls only width.
+// Caret movement, deletion, max length and masking work on grapheme clusters.
+// Text is laid out with the Unicode bidirectional algorithm; in RTL widgets it
+// is right-aligned and the arrow keys are mirrored.
 type TextInput struct {
 	uikit.Base
 
@@ -23,6 +27,15 @@ type TextInput struct {
 	placeholder string
 	caretTick   int
 
+	caret    int // byte offset, always on a grapheme boundary
+	anchor   int // selection anchor; equal to caret when nothing is selected
+	dragging bool
+	scrollX  int
+	maxLen   int
+
+	password bool
+	mask     rune
+
 	// Reusable buffers to avoid allocations on every Update().
 	inputBuf  []rune
 	appendBuf []rune
@@ -33,6 +46,7 @@ func NewTextInput(theme *uikit.Theme, placeholder string) *TextInput {
 
 	w := &TextInput{
 		placeholder: placeholder,
+		mask:        '•',
 	}
 
 	w.Base = uikit.NewBase(cfg)
@@ -43,12 +57,20 @@ func (w *TextInput) Focusable() bool { return true }
 func (w *TextInput) WantsIME() bool  { return true }
 func (w *TextInput) Text() string    { return w.text }
 
+func (w *TextInput) SetPlaceholder(p string) { w.placeholder = p }
+
 // SetText sets the current text value and dispatches a value-change event.
+// The caret is moved to the end of the text.
 func (w *TextInput) SetText(s string) {
+	if w.maxLen > 0 {
+		s = truncateGraphemes(s, w.maxLen)
+	}
 	if w.text == s {
 		return
 	}
 	w.text = s
+	w.caret = len(s)
+	w.anchor = w.caret
 	w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange})
 }
 
@@ -56,6 +78,8 @@ func (w *TextInput) SetText(s string) {
 // Useful internally to batch changes and dispatch once.
 func (w *TextInput) SetTextSilently(s string) {
 	w.text = s
+	w.caret = len(s)
+	w.anchor = w.caret
 }
 
 // AppendText appends a string to the current text and dispatches a value-change event.
@@ -71,16 +95,101 @@ func (w *TextInput) Reset() {
 	w.SetText("")
 }
 
-// removeLastRune removes the last UTF-8 rune from the provided string.
-func removeLastRune(s string) string {
-	if s == "" {
-		return ""
+// SetMaxLength limits the text to n user-perceived characters (grapheme
+// clusters). Use 0 for unlimited.
+func (w *TextInput) SetMaxLength(n int) {
+	w.maxLen = max(n, 0)
+	if w.maxLen > 0 && graphemeCount(w.text) > w.maxLen {
+		w.SetText(truncateGraphemes(w.text, w.maxLen))
+	}
+}
+
+func (w *TextInput) MaxLength() int { return w.maxLen }
+
+// SetPassword enables masking: every grapheme cluster is drawn as the mask rune.
+func (w *TextInput) SetPassword(v bool) { w.password = v }
+
+func (w *TextInput) IsPassword() bool { return w.password }
+
+// SetMask sets the rune used to draw masked text (default '•').
+func (w *TextInput) SetMask(r rune) { w.mask = r }
+
+// Caret returns the caret position as a byte offset into Text().
+func (w *TextInput) Caret() int { return w.caret }
+
+// SetCaret moves the caret to the grapheme boundary at or before byte offset i
+// and clears the selection.
+func (w *TextInput) SetCaret(i int) {
+	w.SetSelection(i, i)
+}
+
+// Selection returns the selected byte range of Text(); start == end when
+// nothing is selected.
+func (w *TextInput) Selection() (start, end int) {
+	return min(w.caret, w.anchor), max(w.caret, w.anchor)
+}
+
+// SetSelection selects the text between anchor and caret (byte offsets,
+// snapped to grapheme boundaries). The caret ends at the caret offset.
+func (w *TextInput) SetSelection(anchor, caret int) {
+	w.anchor = snapGrapheme(w.text, anchor)
+	w.caret = snapGrapheme(w.text, caret)
+	w.caretTick = 0
+}
+
+// SelectAll selects the whole text.
+func (w *TextInput) SelectAll() {
+	w.SetSelection(0, len(w.text))
+}
+
+// SelectedText returns the currently selected text.
+func (w *TextInput) SelectedText() string {
+	a, b := w.Selection()
+	return w.text[a:b]
+}
+
+// toDisplay converts a text offset to an offset in the displayed string,
+// which differ when the text is masked.
+func (w *TextInput) toDisplay(off int) int {
+	if !w.password {
+		return off
 	}
-	_, sz := utf8.DecodeLastRuneInString(s)
-	if sz <= 0 || sz > len(s) {
-		return ""
+
+	return graphemeCount(w.text[:off]) * utf8.RuneLen(w.mask)
+}
+
+// display returns the string drawn for the current text.
+func (w *TextInput) display() string {
+	if !w.password {
+		return w.text
 	}
-	return s[:len(s)-sz]
+
+	return maskGraphemes(w.text, w.mask)
+}
+
+// displayToText converts an offset in the displayed string back to the text.
+func (w *TextInput) displayToText(off int) int {
+	if !w.password {
+		return off
+	}
+
+	return len(truncateGraphemes(w.text, off/utf8.RuneLen(w.mask)))
+}
+
+// hit returns the text offset under the pointer x coordinate.
+func (w *TextInput) hit(ctx *uikit.Context, x int) int {
+	content := w.contentRect(ctx.Theme())
+	t := ctx.Theme().Text()
+	line := layoutBidi(w.display(), w.IsRTL(ctx))
+	off := line.offsetAt(x-content.Min.X+w.scrollX, func(s string) int {
+		return t.Measure(s).IntWidth()
+	})
+
+	return w.displayToText(off)
+}
+
+func (w *TextInput) contentRect(theme *uikit.Theme) image.Rectangle {
+	return common.Inset(w.Measure(false), theme.PadX, theme.PadY)
 }
 
 func (w *TextInput) Update(ctx *uikit.Context) {
@@ -101,12 +210,35 @@ func (w *TextInput) Update(ctx *uikit.Context) {
 		w.caretTick = 0
 	}
 
-	if !focused || !enabled {
+	if !enabled {
+		return
+	}
+
+	// Pointer places the caret at the nearest cluster boundary; dragging or
+	// Shift extends the selection.
+	ptr := ctx.Pointer()
+	if ptr.IsJustDown && ptr.Position.In(r) {
+		off := w.hit(ctx, ptr.Position.X)
+		if shiftPressed() {
+			w.SetSelection(w.anchor, off)
+		} else {
+			w.SetCaret(off)
+		}
+		w.dragging = true
+	} else if w.dragging && ptr.IsDown {
+		w.SetSelection(w.anchor, w.hit(ctx, ptr.Position.X))
+	}
+	if !ptr.IsDown {
+		w.dragging = false
+	}
+
+	if !focused {
 		return
 	}
 
 	original := w.text
 	text := original
+	caret, anchor := w.caret, w.anchor
 
 	// Reuse buffer to avoid allocations.
 	w.inputBuf = ebiten.AppendInputChars(w.inputBuf[:0])
@@ -114,25 +246,55 @@ func (w *TextInput) Update(ctx *uikit.Context) {
 	// Batch normal runes to avoid repeated string concatenations.
 	w.appendBuf = w.appendBuf[:0]
 
+	deleteSelection := func() bool {
+		if caret == anchor {
+			return false
+		}
+		a, b := min(caret, anchor), max(caret, anchor)
+		text = text[:a] + text[b:]
+		caret, anchor = a, a
+		return true
+	}
+
 	flushAppend := func() {
 		if len(w.appendBuf) == 0 {
 			return
 		}
-		text += string(w.appendBuf)
+		deleteSelection()
+		s := fitGraphemes(graphemeCount(text), text[prevGrapheme(text, caret):caret], string(w.appendBuf), w.maxLen)
+		text = text[:caret] + s + text[caret:]
+		caret += len(s)
+		anchor = caret
 		w.appendBuf = w.appendBuf[:0]
 	}
 
+	backspace := func() {
+		if deleteSelection() {
+			return
+		}
+		prev := prevGrapheme(text, caret)
+		text = text[:prev] + text[caret:]
+		caret, anchor = prev, prev
+	}
+
+	move := func(to int) {
+		caret = to
+		if !shiftPressed() {
+			anchor = caret
+		}
+	}
+
 	// IME / input chars
 	for _, ch := range w.inputBuf {
 		// Backspace can arrive as '\b' or DEL.
 		if ch == '\b' || ch == 0x7f {
 			flushAppend()
-			text = removeLastRune(text)
+			backspace()
 			continue
 		}
 
-		// Skip control characters.
-		if ch < 0x20 {
+		// Skip control characters and runes typed along with shortcuts.
+		if ch < 0x20 || shortcutPressed() {
 			continue
 		}
 
@@ -142,8 +304,30 @@ func (w *TextInput) Update(ctx *uikit.Context) {
 	flushAppend()
 
 	// Desktop / fallback backspace handling (Android IME can be inconsistent).
-	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) || inpututil.IsKeyJustPressed(ebiten.KeyDelete) {
-		text = removeLastRune(text)
+	if keyRepeat(ebiten.KeyBackspace) {
+		backspace()
+	}
+	if keyRepeat(ebiten.KeyDelete) && !deleteSelection() {
+		text = text[:caret] + text[nextGrapheme(text, caret):]
+	}
+
+	// Caret movement (logical order; arrows are mirrored in RTL).
+	back, forward := ebiten.KeyLeft, ebiten.KeyRight
+	if w.IsRTL(ctx) {
+		back, forward = forward, back
+	}
+
+	switch {
+	case shortcutPressed() && inpututil.IsKeyJustPressed(ebiten.KeyA):
+		caret, anchor = len(text), 0
+	case keyRepeat(back):
+		move(prevGrapheme(text, caret))
+	case keyRepeat(forward):
+		move(nextGrapheme(text, caret))
+	case inpututil.IsKeyJustPressed(ebiten.KeyHome):
+		move(0)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnd):
+		move(len(text))
 	}
 
 	// Commit focus changes (no text modification).
@@ -151,10 +335,36 @@ func (w *TextInput) Update(ctx *uikit.Context) {
 		ctx.SetFocus(nil)
 	}
 
+	if caret != w.caret || anchor != w.anchor {
+		w.caretTick = 0
+	}
+
 	// Dispatch only once if something actually changed.
+	w.caret, w.anchor = caret, anchor
 	if text != original {
-		w.SetText(text)
+		w.text = text
+		w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange})
+	}
+}
+
+// updateScroll keeps the caret inside the visible width. Text narrower than
+// the view is left-aligned, or right-aligned in RTL (negative scroll).
+func (w *TextInput) updateScroll(textW, caretX, viewW int, rtl bool) {
+	if textW <= viewW {
+		w.scrollX = 0
+		if rtl {
+			w.scrollX = textW - viewW
+		}
+		return
 	}
+
+	if caretX-w.scrollX > viewW {
+		w.scrollX = caretX - viewW
+	}
+	if caretX < w.scrollX {
+		w.scrollX = caretX
+	}
+	w.scrollX = clampInt(w.scrollX, 0, textW-viewW)
 }
 
 func (w *TextInput) Draw(ctx *uikit.Context, dst *ebiten.Image) {
@@ -163,11 +373,12 @@ func (w *TextInput) Draw(ctx *uikit.Context, dst *ebiten.Image) {
 	theme := ctx.Theme()
 	r := w.Measure(false)
 
-	content := common.Inset(r, theme.PadX, theme.PadY)
+	content := w.contentRect(theme)
 	middleY := r.Min.Y + r.Dy()/2
 
 	// Decide what to render: actual text or placeholder.
-	drawStr := w.text
+	drawStr := w.display()
+	caretOff, anchorOff := w.toDisplay(w.caret), w.toDisplay(w.anchor)
 	textCol := theme.TextColor
 	if drawStr == "" && !w.IsFocused() {
 		drawStr = w.placeholder
@@ -175,53 +386,47 @@ func (w *TextInput) Draw(ctx *uikit.Context, dst *ebiten.Image) {
 	}
 
 	t := theme.Text()
-
-	// Horizontal overflow handling (keep the end visible).
-	m := t.Measure(drawStr)
-	textW := m.ImageRect().Dx()
-
-	shiftX := 0
-	if textW > content.Dx() {
-		shiftX = content.Dx() - textW
+	measure := func(s string) int { return t.Measure(s).IntWidth() }
+	line := layoutBidi(drawStr, w.IsRTL(ctx))
+
+	// Horizontal overflow handling (keep the caret visible).
+	textW := measure(line.visual)
+	caretX := line.caretX(caretOff, measure)
+	viewW := content.Dx() - theme.CaretWidthPx - theme.CaretMarginPx
+	w.updateScroll(textW, caretX, viewW, w.IsRTL(ctx))
+	originX := content.Min.X - w.scrollX
+
+	clip := dst.SubImage(image.Rect(content.Min.X, r.Min.Y, content.Max.X, r.Max.Y)).(*ebiten.Image)
+	lineH := t.Measure(" ").IntHeight()
+
+	// Selection highlight (may be split in several visual spans).
+	if w.IsFocused() && caretOff != anchorOff {
+		for _, sp := range line.selectionSpans(min(caretOff, anchorOff), max(caretOff, anchorOff), measure) {
+			sel := image.Rect(originX+sp[0], middleY-lineH/2, originX+sp[1], middleY-lineH/2+lineH)
+			w.DrawRoundedRect(clip, sel, 0, theme.SelectionColor)
+		}
 	}
 
-	// Draw text centered vertically.
+	// Draw text centered vertically, clipped horizontally to the content.
 	t.SetColor(textCol)
-	t.Draw(dst, drawStr, content.Min.X+shiftX, middleY)
+	t.Draw(clip, line.visual, originX, middleY)
 
-	// Caret drawing (end-of-text caret).
+	// Caret drawing.
 	if w.IsFocused() && w.IsEnabled() && theme.CaretWidthPx > 0 {
 		blinkFrames := int(math.Max(1, float64(theme.CaretBlink)/float64(time.Second)*60.0))
 		if (w.caretTick/blinkFrames)%2 == 0 {
-			measureStr := w.text
-			if measureStr == "" {
-				measureStr = " "
-			}
-
-			mc := t.Measure(measureStr)
-
-			cx := content.Min.X + shiftX + mc.IntWidth() + theme.CaretMarginPx
-			if w.text == "" {
-				cx = content.Min.X + shiftX + theme.CaretMarginPx
-			}
-
-			caretH := mc.IntHeight()
-			cy := middleY - (caretH / 2)
+			cx := originX + caretX + theme.CaretMarginPx
+			cy := middleY - (lineH / 2)
 
 			// Clamp caret into content rect.
-			if cx < content.Min.X {
-				cx = content.Min.X
-			}
-			if cx > content.Min.X+content.Dx() {
-				cx = content.Min.X + content.Dx()
-			}
+			cx = clampInt(cx, content.Min.X, content.Max.X-theme.CaretWidthPx)
 
 			vector.DrawFilledRect(
 				dst,
 				float32(cx),
 				float32(cy),
 				float32(theme.CaretWidthPx),
-				float32(caretH),
+				float32(lineH),
 				theme.CaretColor,
 				false,
 			)
@@ -0,0 +1,315 @@
+package widget
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/erparts/go-uikit"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/tinne26/etxt"
+)
+
+var _ uikit.Widget = (*TimePicker)(nil)
+var _ uikit.ValidableWidget = (*TimePicker)(nil)
+
+// TimePicker edits a time of day as hour and minute fields, plus an AM/PM
+// field with Use12Hour.
+// - Click a field or move between fields with Left/Right; Up/Down, the
+// wheel or the stepper on the trailing side change it, wrapping around.
+// - Digits typed into a field move on to the next one once it is complete;
+// A and P pick AM and PM.
+// - Times outside Min/Max (offsets from midnight, zero Max for none) mark
+// the widget invalid; the value is kept.
+// - EventValueChange carries the new time.Time.
+type TimePicker struct {
+	uikit.Base
+
+	Use12Hour bool
+	// MinuteStep is the Up/Down step of the minute field; 0 means 1.
+	MinuteStep int
+	Min, Max   time.Duration
+
+	date         time.Time // day and location of the value
+	hour, minute int
+
+	field   int // focused field: 0 hour, 1 minute, 2 AM/PM
+	typed   string
+	typedAt time.Time
+}
+
+// timeLayout holds the field rectangles, in screen coordinates.
+type timeLayout struct {
+	fields   [3]image.Rectangle
+	colon    image.Rectangle
+	up, down image.Rectangle
+}
+
+// NewTimePicker creates a picker set to midnight.
+func NewTimePicker(theme *uikit.Theme) *TimePicker {
+	cfg := uikit.NewWidgetBaseConfig(theme)
+
+	return &TimePicker{
+		Base: uikit.NewBase(cfg),
+		date: time.Date(1, 1, 1, 0, 0, 0, 0, time.Local),
+	}
+}
+
+func (w *TimePicker) Focusable() bool   { return true }
+func (w *TimePicker) IsValidable() bool { return true }
+
+// Value returns the picked time on the date of the last SetValue.
+func (w *TimePicker) Value() time.Time {
+	y, m, d := w.date.Date()
+	return time.Date(y, m, d, w.hour, w.minute, 0, 0, w.date.Location())
+}
+
+// SetValue takes the hour and minute of t; its date is kept for Value.
+func (w *TimePicker) SetValue(t time.Time) {
+	w.date = t
+	w.SetClock(t.Hour(), t.Minute())
+}
+
+// Clock returns the hour (0-23) and minute.
+func (w *TimePicker) Clock() (hour, minute int) {
+	return w.hour, w.minute
+}
+
+// SetClock sets the hour (0-23) and minute.
+func (w *TimePicker) SetClock(hour, minute int) {
+	hour, minute = clampInt(hour, 0, 23), clampInt(minute, 0, 59)
+	w.validate(hour, minute)
+	if hour == w.hour && minute == w.minute {
+		return
+	}
+
+	w.hour, w.minute = hour, minute
+	w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange, Value: w.Value()})
+}
+
+func (w *TimePicker) validate(hour, minute int) {
+	off := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute
+	if off < w.Min || (w.Max > 0 && off > w.Max) {
+		w.SetInvalid("Time out of range")
+		return
+	}
+	w.ClearInvalid()
+}
+
+func (w *TimePicker) fieldCount() int {
+	if w.Use12Hour {
+		return 3
+	}
+	return 2
+}
+
+// step moves the focused field by n steps, wrapping around. In 12-hour
+// mode the hour keeps its AM/PM half.
+func (w *TimePicker) step(n int) {
+	switch w.field {
+	case 0:
+		if w.Use12Hour {
+			w.SetClock(w.hour/12*12+((w.hour%12+n)%12+12)%12, w.minute)
+		} else {
+			w.SetClock(((w.hour+n)%24+24)%24, w.minute)
+		}
+	case 1:
+		s := max(w.MinuteStep, 1)
+		m := (w.minute/s + n) * s
+		if n < 0 && w.minute%s != 0 {
+			m += s // snap down to the step first
+		}
+		w.SetClock(w.hour, (m%60+60)%60)
+	case 2:
+		w.SetClock((w.hour+12)%24, w.minute)
+	}
+}
+
+// typeDigit enters c into the focused field. A field is complete after two
+// digits, or one that no second digit could follow.
+func (w *TimePicker) typeDigit(ctx *uikit.Context, c rune) {
+	if ctx.Now().Sub(w.typedAt) > time.Second {
+		w.typed = ""
+	}
+	w.typedAt = ctx.Now()
+
+	lo, hi, first := 0, 23, 2
+	switch {
+	case w.field == 1:
+		hi, first = 59, 5
+	case w.Use12Hour:
+		lo, hi, first = 1, 12, 1
+	}
+
+	w.typed += string(c)
+	v, _ := strconv.Atoi(w.typed)
+	if v > hi {
+		w.typed = string(c)
+		v = int(c - '0')
+	}
+
+	if v >= lo {
+		if w.field == 1 {
+			w.SetClock(w.hour, v)
+		} else if w.Use12Hour {
+			w.SetClock(w.hour/12*12+v%12, w.minute)
+		} else {
+			w.SetClock(v, w.minute)
+		}
+	}
+
+	if len(w.typed) == 2 || v > first {
+		w.typed = ""
+		w.field = min(w.field+1, w.fieldCount()-1)
+	}
+}
+
+// layout places the fields on the leading side and the stepper on the
+// trailing side. The digits keep their order in RTL.
+func (w *TimePicker) layout(theme *uikit.Theme, r image.Rectangle, rtl bool) timeLayout {
+	t := theme.Text()
+	fw := t.Measure("00").IntWidth() + theme.SpaceS*2
+	cw := t.Measure(":").IntWidth()
+	aw := t.Measure("PM").IntWidth() + theme.SpaceS*2
+
+	width := fw*2 + cw
+	if w.Use12Hour {
+		width += theme.SpaceS + aw
+	}
+	stepW := theme.ControlH * 2 / 3
+
+	x, sx := r.Min.X+theme.PadX, r.Max.X-stepW
+	if rtl {
+		x, sx = r.Max.X-theme.PadX-width, r.Min.X
+	}
+	y0, y1 := r.Min.Y+theme.SpaceS/2, r.Max.Y-theme.SpaceS/2
+
+	var l timeLayout
+	l.fields[0] = image.Rect(x, y0, x+fw, y1)
+	l.colon = image.Rect(x+fw, y0, x+fw+cw, y1)
+	l.fields[1] = image.Rect(l.colon.Max.X, y0, l.colon.Max.X+fw, y1)
+	x = l.fields[1].Max.X + theme.SpaceS
+	l.fields[2] = image.Rect(x, y0, x+aw, y1)
+
+	mid := r.Min.Y + r.Dy()/2
+	l.up = image.Rect(sx, r.Min.Y, sx+stepW, mid)
+	l.down = image.Rect(sx, mid, sx+stepW, r.Max.Y)
+	return l
+}
+
+func (w *TimePicker) Update(ctx *uikit.Context) {
+	r := w.Measure(false)
+	if r.Dy() == 0 {
+		w.SetFrame(r.Min.X, r.Min.Y, r.Dx())
+		r = w.Measure(false)
+	}
+
+	n := w.fieldCount()
+	w.field = min(w.field, n-1)
+	if !w.IsEnabled() {
+		return
+	}
+
+	l := w.layout(ctx.Theme(), r, w.IsRTL(ctx))
+	ptr := ctx.Pointer()
+	if ptr.IsJustDown && ptr.Position.In(r) {
+		switch p := ptr.Position; {
+		case p.In(l.up):
+			w.step(1)
+		case p.In(l.down):
+			w.step(-1)
+		default:
+			for i := range n {
+				if p.In(l.fields[i]) {
+					w.field, w.typed = i, ""
+				}
+			}
+		}
+	}
+
+	if w.IsHovered() {
+		if _, wy := ebiten.Wheel(); wy != 0 {
+			w.step(int(math.Copysign(1, wy)))
+		}
+	}
+
+	if !w.IsFocused() {
+		return
+	}
+
+	switch {
+	case keyRepeat(ebiten.KeyUp):
+		w.step(1)
+	case keyRepeat(ebiten.KeyDown):
+		w.step(-1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyLeft):
+		w.field, w.typed = max(w.field-1, 0), ""
+	case inpututil.IsKeyJustPressed(ebiten.KeyRight):
+		w.field, w.typed = min(w.field+1, n-1), ""
+	default:
+		for _, c := range ebiten.AppendInputChars(nil) {
+			switch {
+			case c >= '0' && c <= '9' && w.field < 2:
+				w.typeDigit(ctx, c)
+			case w.Use12Hour && (c == 'a' || c == 'A'):
+				w.SetClock(w.hour%12, w.minute)
+			case w.Use12Hour && (c == 'p' || c == 'P'):
+				w.SetClock(w.hour%12+12, w.minute)
+			}
+		}
+	}
+}
+
+func (w *TimePicker) Draw(ctx *uikit.Context, dst *ebiten.Image) {
+	w.Base.Draw(ctx, dst)
+
+	theme := ctx.Theme()
+	r := w.Measure(false)
+	l := w.layout(theme, r, w.IsRTL(ctx))
+
+	col := theme.TextColor
+	if !w.IsEnabled() {
+		col = theme.DisabledColor
+	}
+
+	h := w.hour
+	texts := []string{"", fmt.Sprintf("%02d", w.minute), "AM"}
+	if w.Use12Hour {
+		if h %= 12; h == 0 {
+			h = 12
+		}
+		if w.hour >= 12 {
+			texts[2] = "PM"
+		}
+	}
+	texts[0] = fmt.Sprintf("%02d", h)
+
+	t := theme.Text()
+	t.SetAlign(etxt.Center)
+	t.SetColor(col)
+	for i := range w.fieldCount() {
+		f := l.fields[i]
+		if i == w.field && w.IsFocused() && w.IsEnabled() {
+			w.DrawRoundedRect(dst, f, theme.Radius, lerpColor(theme.SurfaceColor, theme.FocusColor, 0.35))
+		}
+		t.Draw(dst, texts[i], f.Min.X+f.Dx()/2, f.Min.Y+f.Dy()/2)
+	}
+	t.Draw(dst, ":", l.colon.Min.X+l.colon.Dx()/2, l.colon.Min.Y+l.colon.Dy()/2)
+
+	// Stepper.
+	ptr := ctx.Pointer()
+	size := float32(theme.IconSize) * 0.75
+	for _, b := range []struct {
+		r  image.Rectangle
+		dy float32
+	}{{l.up, -1}, {l.down, 1}} {
+		if w.IsEnabled() && w.IsHovered() && ptr.Position.In(b.r) {
+			w.DrawRoundedRect(dst, b.r.Inset(theme.BorderW), theme.Radius, theme.SurfaceHoverColor)
+		}
+		cx, cy := float32(b.r.Min.X+b.r.Dx()/2), float32(b.r.Min.Y+b.r.Dy()/2)
+		drawChevron(dst, cx, cy, size, 0, b.dy, col)
+	}
+}
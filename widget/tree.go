@@ -0,0 +1,684 @@
+package widget
+
+import (
+	"image"
+	"strings"
+	"time"
+
+	"github.com/erparts/go-uikit"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/tinne26/etxt"
+)
+
+var _ uikit.Widget = (*Tree[int])(nil)
+
+// treeTypeAhead is the pause after which type-ahead starts a new prefix.
+const treeTypeAhead = time.Second
+
+// TreeProvider supplies the nodes of a Tree. Children is only called when a
+// node is first expanded, so large or remote hierarchies load lazily.
+type TreeProvider[T comparable] interface {
+	Roots() []T
+	Children(node T) []T
+	HasChildren(node T) bool
+	Label(node T) string
+}
+
+type treeNode[T comparable] struct {
+	value    T
+	parent   *treeNode[T]
+	depth    int
+	leaf     bool
+	loaded   bool
+	children []*treeNode[T]
+	row      int // index in Tree.rows, -1 while hidden
+}
+
+// Tree shows a hierarchy of nodes with expandable branches.
+// - Nodes come from a TreeProvider and are loaded on first expansion.
+// - Only the rows intersecting the viewport are drawn, so expanded trees of
+// 100k nodes stay cheap. With SetHeight the tree scrolls by itself and
+// follows the keyboard cursor; otherwise it can sit in a scrollable layout.
+// - Up/Down move the cursor (Shift extends a multi selection), Right expands
+// or enters a branch, Left collapses or goes to the parent, Space selects,
+// Enter calls OnActivate. Typing jumps to the next row starting with the
+// typed text.
+// - EventValueChange is dispatched when the cursor or the selection changes;
+// it carries the cursor node in Event.Value.
+type Tree[T comparable] struct {
+	uikit.Base
+
+	provider TreeProvider[T]
+	roots    []*treeNode[T]
+	loaded   bool
+	rows     []*treeNode[T] // visible rows, depth first
+	dirty    bool
+
+	expanded map[T]bool
+	selected map[T]bool
+	mode     SelectionMode
+
+	cursor *treeNode[T]
+	anchor *treeNode[T] // start of Shift range selection
+
+	// Indent is the horizontal offset per depth level.
+	Indent int
+
+	// OnActivate is called when Enter is pressed on a node.
+	OnActivate func(node T)
+
+	height int
+	scroll uikit.Scroller
+	reveal bool
+	rtl    bool
+
+	typed   string
+	typedAt time.Time
+}
+
+func NewTree[T comparable](theme *uikit.Theme, provider TreeProvider[T]) *Tree[T] {
+	cfg := uikit.NewWidgetBaseConfig(theme)
+	cfg.DrawSurface = false // drawn over the visible part only
+	cfg.DrawBorder = false
+	cfg.DrawFocus = false // drawn around the cursor row instead
+
+	w := &Tree[T]{
+		Base:     uikit.NewBase(cfg),
+		provider: provider,
+		expanded: make(map[T]bool),
+		selected: make(map[T]bool),
+		Indent:   theme.SpaceL,
+		scroll:   uikit.NewScroller(),
+		dirty:    true,
+	}
+	w.Base.HeightCalculator = w.heightCalculator
+	w.Base.On(uikit.EventPointerDown, w.onPointerDown, false)
+
+	return w
+}
+
+func (w *Tree[T]) heightCalculator() int {
+	if w.height > 0 {
+		return w.height
+	}
+	w.rebuild()
+	return max(len(w.rows), 1) * w.rowH()
+}
+
+func (w *Tree[T]) Focusable() bool { return true }
+
+func (w *Tree[T]) handlesEnter() bool { return w.OnActivate != nil }
+
+// SetHeight fixes the viewport height and makes the tree scroll. Use 0 to
+// show every row.
+func (w *Tree[T]) SetHeight(h int) {
+	w.height = h
+}
+
+func (w *Tree[T]) SetSelectionMode(m SelectionMode) {
+	w.mode = m
+	if m == SelectNone {
+		clear(w.selected)
+	}
+}
+
+// Reload discards the loaded nodes and asks the provider again. Expanded
+// nodes, the selection and the cursor are kept where they still exist.
+func (w *Tree[T]) Reload() {
+	var cur T
+	hasCur := w.cursor != nil
+	if hasCur {
+		cur = w.cursor.value
+	}
+
+	w.roots, w.loaded = nil, false
+	w.cursor, w.anchor = nil, nil
+	w.dirty = true
+	w.rebuild()
+
+	if hasCur {
+		w.cursor = w.find(cur)
+	}
+}
+
+// Refresh reloads the children of a visible node.
+func (w *Tree[T]) Refresh(node T) {
+	n := w.find(node)
+	if n == nil {
+		return
+	}
+
+	if w.cursor != nil && w.cursor != n && isTreeAncestor(n, w.cursor) {
+		w.cursor = n
+	}
+	if w.anchor != nil && isTreeAncestor(n, w.anchor) {
+		w.anchor = nil
+	}
+
+	n.leaf = !w.provider.HasChildren(n.value)
+	n.children, n.loaded = nil, false
+	w.dirty = true
+}
+
+// Expand opens a visible node. It reports whether the node was found.
+func (w *Tree[T]) Expand(node T) bool {
+	return w.setExpanded(w.find(node), true)
+}
+
+// Collapse closes a visible node. It reports whether the node was found.
+func (w *Tree[T]) Collapse(node T) bool {
+	return w.setExpanded(w.find(node), false)
+}
+
+func (w *Tree[T]) IsExpanded(node T) bool { return w.expanded[node] }
+
+func (w *Tree[T]) setExpanded(n *treeNode[T], v bool) bool {
+	if n == nil || n.leaf {
+		return false
+	}
+	if w.expanded[n.value] == v {
+		return true
+	}
+
+	if v {
+		w.expanded[n.value] = true
+	} else {
+		delete(w.expanded, n.value)
+		if w.cursor != nil && isTreeAncestor(n, w.cursor) {
+			w.setCursor(n)
+		}
+	}
+	w.dirty = true
+	return true
+}
+
+func isTreeAncestor[T comparable](a, n *treeNode[T]) bool {
+	for p := n.parent; p != nil; p = p.parent {
+		if p == a {
+			return true
+		}
+	}
+	return false
+}
+
+// Cursor returns the node under the keyboard cursor.
+func (w *Tree[T]) Cursor() (T, bool) {
+	if w.cursor == nil {
+		var zero T
+		return zero, false
+	}
+	return w.cursor.value, true
+}
+
+// SetCursor moves the cursor to a visible node and scrolls it into view.
+func (w *Tree[T]) SetCursor(node T) {
+	if n := w.find(node); n != nil {
+		w.setCursor(n)
+	}
+}
+
+// Selected returns the selected visible nodes, top to bottom.
+func (w *Tree[T]) Selected() []T {
+	w.rebuild()
+
+	var out []T
+	for _, n := range w.rows {
+		if w.selected[n.value] {
+			out = append(out, n.value)
+		}
+	}
+	return out
+}
+
+func (w *Tree[T]) IsSelected(node T) bool { return w.selected[node] }
+
+// SetSelected replaces the selection. In single mode only the first node is
+// kept.
+func (w *Tree[T]) SetSelected(nodes ...T) {
+	clear(w.selected)
+	if w.mode == SelectNone {
+		return
+	}
+	for i, v := range nodes {
+		if i > 0 && w.mode == SelectSingle {
+			break
+		}
+		w.selected[v] = true
+	}
+}
+
+func (w *Tree[T]) ClearSelection() {
+	clear(w.selected)
+}
+
+func (w *Tree[T]) rowH() int {
+	return w.Theme().ControlH
+}
+
+func (w *Tree[T]) newNode(v T, parent *treeNode[T]) *treeNode[T] {
+	n := &treeNode[T]{value: v, parent: parent, row: -1}
+	if parent != nil {
+		n.depth = parent.depth + 1
+	}
+	n.leaf = !w.provider.HasChildren(v)
+	return n
+}
+
+func (w *Tree[T]) load(n *treeNode[T]) {
+	if n.loaded {
+		return
+	}
+	for _, v := range w.provider.Children(n.value) {
+		n.children = append(n.children, w.newNode(v, n))
+	}
+	n.loaded = true
+}
+
+// rebuild flattens the expanded nodes into rows when something changed.
+func (w *Tree[T]) rebuild() {
+	if !w.dirty {
+		return
+	}
+	w.dirty = false
+
+	if !w.loaded {
+		w.roots = w.roots[:0]
+		for _, v := range w.provider.Roots() {
+			w.roots = append(w.roots, w.newNode(v, nil))
+		}
+		w.loaded = true
+	}
+
+	for _, n := range w.rows {
+		n.row = -1
+	}
+	w.rows = w.rows[:0]
+
+	var walk func(ns []*treeNode[T])
+	walk = func(ns []*treeNode[T]) {
+		for _, n := range ns {
+			n.row = len(w.rows)
+			w.rows = append(w.rows, n)
+			if !n.leaf && w.expanded[n.value] {
+				w.load(n)
+				walk(n.children)
+			}
+		}
+	}
+	walk(w.roots)
+
+	if w.cursor != nil && w.cursor.row < 0 {
+		w.cursor = nil
+	}
+	if w.anchor != nil && w.anchor.row < 0 {
+		w.anchor = nil
+	}
+}
+
+// find returns the visible node holding v.
+func (w *Tree[T]) find(v T) *treeNode[T] {
+	w.rebuild()
+	for _, n := range w.rows {
+		if n.value == v {
+			return n
+		}
+	}
+	return nil
+}
+
+// setCursor moves the cursor and reports the change; the selection may have
+// changed too, so it dispatches even when the cursor stays put.
+func (w *Tree[T]) setCursor(n *treeNode[T]) {
+	w.cursor = n
+	w.reveal = true
+	w.Dispatch(uikit.Event{Widget: w, Type: uikit.EventValueChange, Value: n.value})
+}
+
+// selectRange selects the rows between the anchor and n.
+func (w *Tree[T]) selectRange(n *treeNode[T]) {
+	a := w.anchor
+	if a == nil {
+		a = n
+	}
+
+	lo, hi := min(a.row, n.row), max(a.row, n.row)
+	for _, r := range w.rows[lo : hi+1] {
+		w.selected[r.value] = true
+	}
+}
+
+// pick moves the cursor to n and updates the selection like a click with
+// the given modifiers.
+func (w *Tree[T]) pick(n *treeNode[T], toggle, extend bool) {
+	switch w.mode {
+	case SelectSingle:
+		clear(w.selected)
+		w.selected[n.value] = true
+	case SelectMulti:
+		switch {
+		case extend:
+			if !toggle {
+				clear(w.selected)
+			}
+			w.selectRange(n)
+		case toggle:
+			if w.selected[n.value] {
+				delete(w.selected, n.value)
+			} else {
+				w.selected[n.value] = true
+			}
+			w.anchor = n
+		default:
+			clear(w.selected)
+			w.selected[n.value] = true
+			w.anchor = n
+		}
+	}
+	w.setCursor(n)
+}
+
+// viewport returns the rows area on screen and the y of the first row.
+func (w *Tree[T]) viewport() (image.Rectangle, int) {
+	r := w.Measure(false)
+	top := r.Min.Y
+	if w.height > 0 {
+		top -= w.scroll.ScrollY
+	}
+	return r, top
+}
+
+// rowRect returns the screen rect of row i.
+func (w *Tree[T]) rowRect(i int) image.Rectangle {
+	r, top := w.viewport()
+	y := top + i*w.rowH()
+	return image.Rect(r.Min.X, y, r.Max.X, y+w.rowH())
+}
+
+// arrowRect returns the disclosure arrow area of n within its row.
+func (w *Tree[T]) arrowRect(n *treeNode[T], row image.Rectangle, rtl bool) image.Rectangle {
+	theme := w.Theme()
+	size := w.rowH()
+	x := row.Min.X + theme.SpaceS + n.depth*w.Indent
+	if rtl {
+		x = row.Max.X - theme.SpaceS - n.depth*w.Indent - size
+	}
+	return image.Rect(x, row.Min.Y, x+size, row.Max.Y)
+}
+
+// rowAt returns the node under p, or nil.
+func (w *Tree[T]) rowAt(p image.Point) *treeNode[T] {
+	r, top := w.viewport()
+	if !p.In(r) || p.Y < top {
+		return nil
+	}
+
+	i := (p.Y - top) / w.rowH()
+	if i < 0 || i >= len(w.rows) {
+		return nil
+	}
+	return w.rows[i]
+}
+
+func (w *Tree[T]) onPointerDown(e uikit.Event) bool {
+	if !w.IsEnabled() || e.Pointer == nil {
+		return false
+	}
+
+	w.rebuild()
+	n := w.rowAt(e.Pointer.Position)
+	if n == nil {
+		return false
+	}
+
+	if !n.leaf && e.Pointer.Position.In(w.arrowRect(n, w.rowRect(n.row), w.rtl)) {
+		w.setExpanded(n, !w.expanded[n.value])
+		return false
+	}
+
+	w.pick(n, shortcutPressed(), shiftPressed())
+	return false
+}
+
+func (w *Tree[T]) Update(ctx *uikit.Context) {
+	r := w.Measure(false)
+	w.SetFrame(r.Min.X, r.Min.Y, r.Dx())
+	w.rtl = w.IsRTL(ctx)
+	w.rebuild()
+
+	if w.IsEnabled() && w.IsFocused() {
+		w.updateKeys(ctx)
+		w.rebuild()
+	}
+
+	if w.height <= 0 {
+		w.reveal = false
+		return
+	}
+
+	contentH := len(w.rows) * w.rowH()
+	w.scroll.Update(ctx, r, contentH)
+	if w.reveal && w.cursor != nil {
+		top := w.cursor.row * w.rowH()
+		w.scroll.Reveal(top, top+w.rowH(), r.Dy())
+	}
+	w.reveal = false
+	w.scroll.Clamp(r.Dy(), contentH)
+}
+
+func (w *Tree[T]) updateKeys(ctx *uikit.Context) {
+	n := len(w.rows)
+	if n == 0 {
+		return
+	}
+
+	cur := 0
+	if w.cursor != nil {
+		cur = w.cursor.row
+	} else if w.anyKeyPressed() {
+		w.pick(w.rows[0], false, false)
+		return
+	}
+
+	page := 10
+	if w.height > 0 {
+		page = max(w.height/w.rowH()-1, 1)
+	}
+
+	// Ctrl moves the cursor alone, leaving a multi selection untouched.
+	move := func(i int) {
+		row := w.rows[clampInt(i, 0, n-1)]
+		if w.mode == SelectMulti && shortcutPressed() && !shiftPressed() {
+			w.setCursor(row)
+			return
+		}
+		w.pick(row, false, shiftPressed())
+	}
+
+	expand, collapse := ebiten.KeyRight, ebiten.KeyLeft
+	if w.rtl {
+		expand, collapse = collapse, expand
+	}
+
+	typing := w.typed != "" && ctx.Now().Sub(w.typedAt) < treeTypeAhead
+	switch {
+	case keyRepeat(ebiten.KeyUp):
+		move(cur - 1)
+	case keyRepeat(ebiten.KeyDown):
+		move(cur + 1)
+	case keyRepeat(ebiten.KeyPageUp):
+		move(cur - page)
+	case keyRepeat(ebiten.KeyPageDown):
+		move(cur + page)
+	case inpututil.IsKeyJustPressed(ebiten.KeyHome):
+		move(0)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnd):
+		move(n - 1)
+	case keyRepeat(expand):
+		c := w.rows[cur]
+		switch {
+		case c.leaf:
+		case !w.expanded[c.value]:
+			w.setExpanded(c, true)
+		default:
+			w.rebuild()
+			if c.row+1 < len(w.rows) && w.rows[c.row+1].parent == c {
+				w.pick(w.rows[c.row+1], false, false)
+			}
+		}
+	case keyRepeat(collapse):
+		c := w.rows[cur]
+		if !c.leaf && w.expanded[c.value] {
+			w.setExpanded(c, false)
+		} else if c.parent != nil {
+			w.pick(c.parent, false, false)
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeySpace) && !typing:
+		if w.mode == SelectMulti {
+			w.pick(w.rows[cur], true, false)
+		} else {
+			w.pick(w.rows[cur], false, false)
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyKPEnter):
+		if w.OnActivate != nil {
+			w.OnActivate(w.rows[cur].value)
+		}
+	default:
+		w.typeAhead(ctx, cur, typing)
+	}
+}
+
+// anyKeyPressed reports whether a navigation key was pressed, used to place
+// the cursor on the first row when there is none yet.
+func (w *Tree[T]) anyKeyPressed() bool {
+	for _, k := range []ebiten.Key{ebiten.KeyUp, ebiten.KeyDown, ebiten.KeyHome, ebiten.KeyEnd, ebiten.KeyPageUp, ebiten.KeyPageDown} {
+		if inpututil.IsKeyJustPressed(k) {
+			return true
+		}
+	}
+	return false
+}
+
+// typeAhead moves the cursor to the next row whose label starts with the
+// text typed so far. Repeating a single letter cycles through its matches.
+func (w *Tree[T]) typeAhead(ctx *uikit.Context, cur int, typing bool) {
+	if shortcutPressed() {
+		return
+	}
+
+	chars := ebiten.AppendInputChars(nil)
+	if len(chars) == 0 {
+		return
+	}
+	if !typing {
+		w.typed = ""
+	}
+	if w.typed == "" && chars[0] == ' ' {
+		return
+	}
+
+	w.typed += strings.ToLower(string(chars))
+	w.typedAt = ctx.Now()
+
+	start := cur
+	if len([]rune(w.typed)) == 1 || w.cursor == nil {
+		start = cur + 1
+	}
+
+	n := len(w.rows)
+	for k := 0; k < n; k++ {
+		row := w.rows[(start+k)%n]
+		if strings.HasPrefix(strings.ToLower(w.provider.Label(row.value)), w.typed) {
+			w.pick(row, false, false)
+			return
+		}
+	}
+}
+
+func (w *Tree[T]) Draw(ctx *uikit.Context, dst *ebiten.Image) {
+	theme := ctx.Theme()
+	rtl := w.IsRTL(ctx)
+	w.rebuild()
+
+	r, top := w.viewport()
+	clip := r.Intersect(dst.Bounds())
+	if clip.Empty() {
+		return
+	}
+
+	bg := theme.SurfaceColor
+	if !w.IsEnabled() {
+		bg = theme.SurfacePressedColor
+	}
+	if w.height > 0 {
+		w.DrawRoundedRect(dst, r, theme.Radius, bg)
+	} else {
+		w.DrawRoundedRect(dst, clip, 0, bg)
+	}
+
+	sub := dst.SubImage(clip).(*ebiten.Image)
+	rowH := w.rowH()
+	first := max((clip.Min.Y-top)/rowH, 0)
+	last := min((clip.Max.Y-top+rowH-1)/rowH, len(w.rows))
+
+	var hover *treeNode[T]
+	if w.IsHovered() && w.IsEnabled() {
+		hover = w.rowAt(ctx.Pointer().Position)
+	}
+
+	t := theme.Text()
+	for i := first; i < last; i++ {
+		n := w.rows[i]
+		row := image.Rect(r.Min.X, top+i*rowH, r.Max.X, top+(i+1)*rowH)
+
+		switch {
+		case w.selected[n.value]:
+			w.DrawRoundedRect(sub, row, 0, theme.SelectionColor)
+		case n == hover:
+			w.DrawRoundedRect(sub, row, 0, theme.SurfaceHoverColor)
+		}
+
+		textCol := theme.TextColor
+		if !w.IsEnabled() {
+			textCol = theme.DisabledColor
+		}
+
+		arrow := w.arrowRect(n, row, rtl)
+		if !n.leaf {
+			cx := float32(arrow.Min.X + arrow.Dx()/2)
+			cy := float32(arrow.Min.Y + arrow.Dy()/2)
+			size := float32(rowH) / 3
+			switch {
+			case w.expanded[n.value]:
+				drawChevron(sub, cx, cy, size, 0, 1, textCol)
+			case rtl:
+				drawChevron(sub, cx, cy, size, -1, 0, textCol)
+			default:
+				drawChevron(sub, cx, cy, size, 1, 0, textCol)
+			}
+		}
+
+		t.SetColor(textCol)
+		label := visualText(w.provider.Label(n.value), rtl)
+		cy := row.Min.Y + rowH/2
+		if rtl {
+			t.SetAlign(etxt.Right | etxt.VertCenter)
+			t.Draw(sub, label, arrow.Min.X, cy)
+		} else {
+			t.SetAlign(etxt.Left | etxt.VertCenter)
+			t.Draw(sub, label, arrow.Max.X, cy)
+		}
+
+		if n == w.cursor && w.IsFocused() && w.IsEnabled() {
+			w.DrawRoundedBorder(sub, row, theme.Radius, theme.FocusRingW, theme.FocusColor)
+		}
+	}
+
+	if w.height > 0 {
+		w.DrawRoundedBorder(dst, r, theme.Radius, theme.BorderW, theme.BorderColor)
+		if w.IsFocused() && w.IsEnabled() && w.cursor == nil {
+			w.DrawRoundedBorder(dst, r, theme.Radius, theme.FocusRingW, theme.FocusColor)
+		}
+		w.scroll.DrawBar(sub, theme, clip.Dx(), clip.Dy(), len(w.rows)*rowH)
+	}
+}